@@ -0,0 +1,221 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultInitialInterval is a delay before the first retry.
+	DefaultInitialInterval = time.Second
+	// DefaultMaxInterval caps the delay between retries.
+	DefaultMaxInterval = time.Minute
+	// DefaultMaxElapsedTime is the time after which retries stop and the error is returned.
+	DefaultMaxElapsedTime = 5 * time.Minute
+	// DefaultMultiplier is a factor the delay grows by after each retry.
+	DefaultMultiplier = 2
+	// DefaultJitter randomizes delays by +/-50%.
+	DefaultJitter = 0.5
+)
+
+// Policy describes exponential backoff with jitter.
+type Policy struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	MaxElapsedTime  time.Duration
+	Multiplier      float64
+	Jitter          float64
+}
+
+// DefaultPolicy returns policy with default values.
+func DefaultPolicy() Policy {
+	return Policy{
+		InitialInterval: DefaultInitialInterval,
+		MaxInterval:     DefaultMaxInterval,
+		MaxElapsedTime:  DefaultMaxElapsedTime,
+		Multiplier:      DefaultMultiplier,
+		Jitter:          DefaultJitter,
+	}
+}
+
+// PermanentError stops retries.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent wraps error so that it's not retried.
+func Permanent(err error) error {
+	return &PermanentError{Err: err}
+}
+
+// StatusError is returned for HTTP responses which should be retried.
+type StatusError struct {
+	StatusCode int
+	Status     string
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s (retry after %s)", e.Status, e.RetryAfter)
+	}
+	return e.Status
+}
+
+// Do runs fn until it succeeds, returns a permanent error or max elapsed time passes.
+// Delay set by StatusError.RetryAfter takes precedence over the computed backoff.
+func (p Policy) Do(ctx context.Context, fn func() error) error {
+	start := time.Now()
+	interval := p.InitialInterval
+	for {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		var permanent *PermanentError
+		if errors.As(err, &permanent) {
+			return permanent.Err
+		}
+
+		delay := p.jitter(interval)
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			delay = statusErr.RetryAfter
+		}
+		if p.MaxElapsedTime > 0 && time.Since(start)+delay > p.MaxElapsedTime {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+
+		interval = time.Duration(float64(interval) * p.Multiplier)
+		if p.MaxInterval > 0 && interval > p.MaxInterval {
+			interval = p.MaxInterval
+		}
+	}
+}
+
+// jitter randomizes the interval by Jitter factor.
+func (p Policy) jitter(interval time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return interval
+	}
+	delta := p.Jitter * float64(interval)
+	//nolint:gosec // jitter doesn't need a secure random source
+	return time.Duration(float64(interval) - delta + rand.Float64()*2*delta)
+}
+
+// ParseRetryAfter parses Retry-After header value in seconds or HTTP date.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// retryableStatus returns true for HTTP statuses worth retrying.
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// Transport retries requests rejected with HTTP 429 or temporary server errors.
+// Network errors are returned as is, since the request might have been processed.
+type Transport struct {
+	Base   http.RoundTripper
+	Policy Policy
+}
+
+// NewClient returns HTTP client retrying requests with the policy.
+func NewClient(p Policy) *http.Client {
+	return &http.Client{
+		Transport: &Transport{Policy: p},
+	}
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var resp *http.Response
+	attempt := 0
+	err := t.Policy.Do(req.Context(), func() error {
+		attempt++
+		r := req
+		if attempt > 1 && req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return Permanent(fmt.Errorf("cannot retry %s %s: request body can't be rewound", req.Method, req.URL.Redacted()))
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return Permanent(err)
+			}
+			r = req.Clone(req.Context())
+			r.Body = body
+		}
+
+		var err error
+		resp, err = t.base().RoundTrip(r)
+		if err != nil {
+			return Permanent(err)
+		}
+		if !retryableStatus(resp.StatusCode) {
+			return nil
+		}
+		statusErr := &StatusError{
+			StatusCode: resp.StatusCode,
+			Status:     fmt.Sprintf("%s %s: %s", req.Method, req.URL.Redacted(), resp.Status),
+			RetryAfter: ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		resp = nil
+		return statusErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
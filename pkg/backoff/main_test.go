@@ -0,0 +1,208 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBackoff(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Backoff")
+}
+
+var errTemporary = errors.New("temporary")
+
+func testPolicy() Policy {
+	return Policy{
+		InitialInterval: time.Millisecond,
+		MaxInterval:     5 * time.Millisecond,
+		MaxElapsedTime:  time.Second,
+		Multiplier:      2,
+		Jitter:          0.5,
+	}
+}
+
+// fakeServer returns 429 for the first `failures` requests.
+func fakeServer(failures int32, retryAfter string) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		if n <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	return srv, &calls
+}
+
+var _ = Describe("Policy", func() {
+	It("retries until success", func() {
+		calls := 0
+		err := testPolicy().Do(context.Background(), func() error {
+			calls++
+			if calls < 3 {
+				return errTemporary
+			}
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls).To(Equal(3))
+	})
+
+	It("stops on permanent errors", func() {
+		calls := 0
+		err := testPolicy().Do(context.Background(), func() error {
+			calls++
+			return Permanent(errTemporary)
+		})
+		Expect(err).To(MatchError(errTemporary))
+		Expect(calls).To(Equal(1))
+	})
+
+	It("gives up after max elapsed time", func() {
+		p := testPolicy()
+		p.MaxElapsedTime = 20 * time.Millisecond
+		start := time.Now()
+		err := p.Do(context.Background(), func() error {
+			return errTemporary
+		})
+		Expect(err).To(MatchError(errTemporary))
+		Expect(time.Since(start)).To(BeNumerically("<", 100*time.Millisecond))
+	})
+
+	It("stops when context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		p := testPolicy()
+		p.InitialInterval = time.Hour
+		p.MaxElapsedTime = 2 * time.Hour
+		err := p.Do(ctx, func() error {
+			return errTemporary
+		})
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(err).To(MatchError(errTemporary))
+	})
+})
+
+var _ = DescribeTable("ParseRetryAfter",
+	func(value string, expected time.Duration) {
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		Expect(ParseRetryAfter(value, now)).To(Equal(expected))
+	},
+	Entry("Empty", "", time.Duration(0)),
+	Entry("Seconds", "120", 2*time.Minute),
+	Entry("Negative", "-1", time.Duration(0)),
+	Entry("Date", "Mon, 01 Jan 2024 00:00:30 GMT", 30*time.Second),
+	Entry("Past date", "Sun, 31 Dec 2023 00:00:00 GMT", time.Duration(0)),
+	Entry("Garbage", "soon", time.Duration(0)),
+)
+
+var _ = Describe("Transport", func() {
+	It("retries requests rejected with 429", func() {
+		srv, calls := fakeServer(2, "0")
+		defer srv.Close()
+
+		client := NewClient(testPolicy())
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL, strings.NewReader("payload"))
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(Equal("payload"))
+		Expect(calls.Load()).To(Equal(int32(3)))
+	})
+
+	It("surfaces the error when Retry-After exceeds max elapsed time", func() {
+		srv, calls := fakeServer(100, "120")
+		defer srv.Close()
+
+		client := NewClient(testPolicy())
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, http.NoBody)
+		Expect(err).NotTo(HaveOccurred())
+		start := time.Now()
+		resp, err := client.Do(req)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		var statusErr *StatusError
+		Expect(errors.As(err, &statusErr)).To(BeTrue())
+		Expect(statusErr.StatusCode).To(Equal(http.StatusTooManyRequests))
+		Expect(statusErr.RetryAfter).To(Equal(2 * time.Minute))
+		Expect(calls.Load()).To(Equal(int32(1)))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+
+	It("gives up on persistent 429s", func() {
+		srv, calls := fakeServer(100, "")
+		defer srv.Close()
+
+		p := testPolicy()
+		p.MaxElapsedTime = 50 * time.Millisecond
+		client := NewClient(p)
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, http.NoBody)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Do(req)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		Expect(err).To(MatchError(ContainSubstring("429 Too Many Requests")))
+		Expect(calls.Load()).To(BeNumerically(">", 1))
+	})
+
+	It("doesn't retry other client errors", func() {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer srv.Close()
+
+		client := NewClient(testPolicy())
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, http.NoBody)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		Expect(calls.Load()).To(Equal(int32(1)))
+	})
+})
+
+var _ = Describe("Settings", func() {
+	It("uses defaults", func() {
+		var s *Settings
+		Expect(s.Policy()).To(Equal(DefaultPolicy()))
+		Expect((&Settings{}).Policy()).To(Equal(DefaultPolicy()))
+	})
+
+	It("overrides defaults", func() {
+		p := (&Settings{
+			InitialIntervalSeconds: 0.5,
+			MaxIntervalSeconds:     10,
+			MaxElapsedSeconds:      60,
+			Multiplier:             3,
+		}).Policy()
+		Expect(p.InitialInterval).To(Equal(500 * time.Millisecond))
+		Expect(p.MaxInterval).To(Equal(10 * time.Second))
+		Expect(p.MaxElapsedTime).To(Equal(time.Minute))
+		Expect(p.Multiplier).To(Equal(3.0))
+	})
+})
@@ -0,0 +1,36 @@
+package backoff
+
+import "time"
+
+// Settings holds retry settings for API calls.
+type Settings struct {
+	InitialIntervalSeconds float64 `yaml:"initial_interval_seconds,omitempty"`
+	MaxIntervalSeconds     float64 `yaml:"max_interval_seconds,omitempty"`
+	MaxElapsedSeconds      float64 `yaml:"max_elapsed_seconds,omitempty"`
+	Multiplier             float64 `yaml:"multiplier,omitempty"`
+}
+
+// Policy returns retry policy, using defaults for unset values.
+func (s *Settings) Policy() Policy {
+	p := DefaultPolicy()
+	if s == nil {
+		return p
+	}
+	if s.InitialIntervalSeconds > 0 {
+		p.InitialInterval = seconds(s.InitialIntervalSeconds)
+	}
+	if s.MaxIntervalSeconds > 0 {
+		p.MaxInterval = seconds(s.MaxIntervalSeconds)
+	}
+	if s.MaxElapsedSeconds > 0 {
+		p.MaxElapsedTime = seconds(s.MaxElapsedSeconds)
+	}
+	if s.Multiplier > 1 {
+		p.Multiplier = s.Multiplier
+	}
+	return p
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,164 @@
+package calendar
+
+import (
+	"crypto/sha1" //nolint:gosec
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/fsutil"
+	"github.com/vrutkovs/todohub/pkg/ical"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/source"
+)
+
+// ProdID identifies todohub in generated feed.
+const ProdID = "-//todohub//calendar//EN"
+
+// Feed renders synced items of all sources as VTODOs.
+type Feed struct {
+	settings *Settings
+	seen     *source.FirstSeen
+	mu       sync.Mutex
+	order    []string
+	lists    map[string]map[string][]item
+	now      func() time.Time
+	logger   *logrus.Logger
+}
+
+// item is a synced issue with the time it was first seen in the list.
+type item struct {
+	issue issue.Issue
+	since time.Time
+}
+
+// New returns iCalendar feed.
+func New(s *Settings, logger *logrus.Logger) (*Feed, error) {
+	seen, err := source.NewFirstSeen(s.StatePath)
+	if err != nil {
+		return nil, err
+	}
+	return &Feed{
+		settings: s,
+		seen:     seen,
+		lists:    make(map[string]map[string][]item),
+		now:      time.Now,
+		logger:   logger,
+	}, nil
+}
+
+// ListSynced implements storage.ListObserver.
+// Feed file is rewritten if path is set.
+func (f *Feed) ListSynced(sourceID, list string, issues []issue.Issue) {
+	logger := f.logger.WithFields(logrus.Fields{"calendar": f.settings.Path, "list": list})
+	since, err := f.seen.Update(sourceID, list, issues, f.now())
+	if err != nil {
+		logger.WithError(err).Warn("failed to save first seen times")
+	}
+	items := make([]item, len(issues))
+	for n, i := range issues {
+		items[n] = item{issue: i, since: since[n]}
+	}
+
+	f.mu.Lock()
+	sources, ok := f.lists[list]
+	if !ok {
+		sources = make(map[string][]item)
+		f.lists[list] = sources
+		f.order = append(f.order, list)
+	}
+	sources[sourceID] = items
+	data := f.calendar().String()
+	f.mu.Unlock()
+
+	if f.settings.Path == "" {
+		return
+	}
+	// calendar clients never fetch partial feed
+	if err := fsutil.WriteFile(f.settings.Path, []byte(data), 0o644); err != nil {
+		logger.WithError(err).Error("failed to write calendar")
+	}
+}
+
+// due returns due date from source or derived from list SLA.
+// Zero time means no due date, allDay is set for dates without time of day.
+func (f *Feed) due(list string, i item) (time.Time, bool) {
+	if due := issue.Due(i.issue); !due.IsZero() {
+		return due, due.Equal(due.Truncate(24 * time.Hour))
+	}
+	if hours, ok := f.settings.SLAHours[list]; ok && hours > 0 {
+		return i.since.Add(time.Duration(hours * float64(time.Hour))), false
+	}
+	return time.Time{}, false
+}
+
+// uid returns stable VTODO UID for the item in the list.
+func uid(sourceID, list string, i issue.Issue) string {
+	sum := sha1.Sum([]byte(sourceID + "\x00" + list + "\x00" + source.ItemID(i))) //nolint:gosec
+	return hex.EncodeToString(sum[:]) + "@todohub"
+}
+
+// calendar builds VCALENDAR, must be called with lock held.
+func (f *Feed) calendar() *ical.Component {
+	now := f.now()
+	cal := ical.NewCalendar(ProdID)
+	cal.Set("X-WR-CALNAME", "todohub")
+	for _, list := range f.order {
+		sourceIDs := make([]string, 0, len(f.lists[list]))
+		for sourceID := range f.lists[list] {
+			sourceIDs = append(sourceIDs, sourceID)
+		}
+		sort.Strings(sourceIDs)
+		for _, sourceID := range sourceIDs {
+			for _, i := range f.lists[list][sourceID] {
+				cal.Children = append(cal.Children, f.vtodo(now, sourceID, list, i))
+			}
+		}
+	}
+	return cal
+}
+
+func (f *Feed) vtodo(now time.Time, sourceID, list string, i item) *ical.Component {
+	vtodo := &ical.Component{Name: "VTODO"}
+	vtodo.SetRaw("UID", uid(sourceID, list, i.issue))
+	vtodo.SetTime("DTSTAMP", now)
+	vtodo.SetTime("DTSTART", i.since)
+	vtodo.Set("SUMMARY", i.issue.Title())
+	vtodo.SetRaw("STATUS", "NEEDS-ACTION")
+	if i.issue.URL() != "" {
+		vtodo.SetRaw("URL", i.issue.URL())
+	}
+	categories := []string{list}
+	if i.issue.Repo() != "" {
+		categories = append(categories, i.issue.Repo())
+	}
+	vtodo.SetList("CATEGORIES", categories)
+	if description := issue.Description(i.issue); description != "" {
+		vtodo.Set("DESCRIPTION", description)
+	}
+	due, allDay := f.due(list, i)
+	switch {
+	case due.IsZero():
+	case allDay:
+		// DTSTART must have the same value type as DUE
+		vtodo.SetDate("DTSTART", i.since)
+		vtodo.SetDate("DUE", due)
+	default:
+		vtodo.SetTime("DUE", due)
+	}
+	return vtodo
+}
+
+// ServeHTTP renders the feed.
+func (f *Feed) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	f.mu.Lock()
+	data := f.calendar().String()
+	f.mu.Unlock()
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	if _, err := w.Write([]byte(data)); err != nil {
+		f.logger.WithError(err).Debug("failed to send calendar")
+	}
+}
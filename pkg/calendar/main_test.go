@@ -0,0 +1,129 @@
+package calendar
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/ical"
+	"github.com/vrutkovs/todohub/pkg/issue"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCalendar(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Calendar")
+}
+
+type testIssue struct {
+	title string
+	url   string
+	repo  string
+	due   time.Time
+}
+
+func (i testIssue) Title() string  { return i.title }
+func (i testIssue) URL() string    { return i.url }
+func (i testIssue) Repo() string   { return i.repo }
+func (i testIssue) Due() time.Time { return i.due }
+
+var _ = Describe("Feed", func() {
+	var (
+		f   *Feed
+		now time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		dir := GinkgoT().TempDir()
+		var err error
+		f, err = New(&Settings{
+			Path:      filepath.Join(dir, "todohub.ics"),
+			SLAHours:  map[string]float64{"To review": 48},
+			StatePath: filepath.Join(dir, "state.json"),
+		}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		f.now = func() time.Time { return now }
+	})
+
+	todos := func() []*ical.Component {
+		data, err := os.ReadFile(f.settings.Path)
+		Expect(err).NotTo(HaveOccurred())
+		cal, err := ical.Decode(strings.NewReader(string(data)))
+		Expect(err).NotTo(HaveOccurred())
+		return cal.Find("VTODO")
+	}
+
+	It("writes items with due dates", func() {
+		f.ListSynced("jira", "Assigned", []issue.Issue{
+			testIssue{title: "Bug", url: "https://issues.example.com/browse/ABC-1", repo: "ABC", due: time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)},
+			testIssue{title: "Chore"},
+		})
+		f.ListSynced("github", "To review", []issue.Issue{testIssue{title: "Fix", url: "https://github.com/o/r/pull/1", repo: "o/r"}})
+
+		result := todos()
+		Expect(result).To(HaveLen(3))
+		Expect(result[0].Get("SUMMARY")).To(Equal("Bug"))
+		Expect(result[0].Get("URL")).To(Equal("https://issues.example.com/browse/ABC-1"))
+		Expect(result[0].GetList("CATEGORIES")).To(Equal([]string{"Assigned", "ABC"}))
+		Expect(result[0].Properties).To(ContainElements(
+			ical.Property{Name: "DTSTART", Params: "VALUE=DATE", Value: "20240501"},
+			ical.Property{Name: "DUE", Params: "VALUE=DATE", Value: "20240503"},
+		))
+		Expect(result[1].Get("SUMMARY")).To(Equal("Chore"))
+		Expect(result[1].Get("DUE")).To(BeEmpty())
+		Expect(result[2].Get("SUMMARY")).To(Equal("Fix"))
+		Expect(result[2].Get("DUE")).To(Equal("20240503T100000Z"))
+	})
+
+	It("keeps first seen time and UID between syncs", func() {
+		fix := testIssue{title: "Fix", url: "https://github.com/o/r/pull/1"}
+		f.ListSynced("github", "To review", []issue.Issue{fix})
+		first := todos()[0]
+
+		now = now.Add(time.Hour)
+		f.ListSynced("github", "To review", []issue.Issue{fix, testIssue{title: "Bump"}})
+		result := todos()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Get("UID")).To(Equal(first.Get("UID")))
+		Expect(result[0].Get("DUE")).To(Equal("20240503T100000Z"))
+		Expect(result[0].Get("DTSTAMP")).To(Equal("20240501T110000Z"))
+		Expect(result[1].Get("DUE")).To(Equal("20240503T110000Z"))
+
+		f.ListSynced("github", "To review", nil)
+		Expect(todos()).To(BeEmpty())
+	})
+
+	It("keeps SLA deadlines across restarts", func() {
+		f.ListSynced("github", "To review", []issue.Issue{testIssue{title: "Fix"}})
+
+		restarted, err := New(f.settings, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		restarted.now = func() time.Time { return now.Add(time.Hour) }
+		restarted.ListSynced("github", "To review", []issue.Issue{testIssue{title: "Fix"}})
+		Expect(todos()[0].Get("DUE")).To(Equal("20240503T100000Z"))
+	})
+
+	It("serves the feed", func() {
+		f.ListSynced("github", "To review", []issue.Issue{testIssue{title: "Fix"}})
+		srv := httptest.NewServer(f)
+		defer srv.Close()
+		resp, err := http.Get(srv.URL) //nolint:noctx
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.Header.Get("Content-Type")).To(Equal("text/calendar; charset=utf-8"))
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		data, err := os.ReadFile(f.settings.Path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(Equal(string(data)))
+	})
+})
@@ -0,0 +1,14 @@
+package calendar
+
+// Settings holds iCalendar feed settings.
+// The feed is served on /calendar.ics when server is enabled.
+type Settings struct {
+	// Path is an optional .ics file rewritten after each list sync.
+	Path string `yaml:"path,omitempty"`
+	// SLAHours maps list names to hours an item may stay in the list.
+	// Items without due date in source are due that long after todohub first saw them
+	// in the list. First seen times start over on restart unless StatePath is set.
+	SLAHours map[string]float64 `yaml:"sla_hours,omitempty"`
+	// StatePath is an optional JSON file keeping times items were first seen.
+	StatePath string `yaml:"state_path,omitempty"`
+}
@@ -0,0 +1,177 @@
+package dashboard
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/fsutil"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/source"
+)
+
+// Dashboard renders synced lists of all sources as a single HTML page.
+// Lists are columns, items with the same list name from different sources share a column.
+type Dashboard struct {
+	settings *Settings
+	seen     *source.FirstSeen
+	mu       sync.Mutex
+	order    []string
+	lists    map[string]map[string][]Card
+	updated  time.Time
+	now      func() time.Time
+	logger   *logrus.Logger
+}
+
+// Card is a rendered item.
+type Card struct {
+	Title  string
+	URL    string
+	Repo   string
+	Source string
+	// Since is the time item was first seen in the list by todohub,
+	// not the time it was created in the source.
+	Since time.Time
+}
+
+// Column is a rendered list.
+type Column struct {
+	Name  string
+	Cards []Card
+}
+
+type page struct {
+	Title   string
+	Refresh int
+	Updated time.Time
+	Columns []Column
+}
+
+// New returns dashboard.
+func New(s *Settings, logger *logrus.Logger) (*Dashboard, error) {
+	seen, err := source.NewFirstSeen(s.StatePath)
+	if err != nil {
+		return nil, err
+	}
+	return &Dashboard{
+		settings: s,
+		seen:     seen,
+		lists:    make(map[string]map[string][]Card),
+		now:      time.Now,
+		logger:   logger,
+	}, nil
+}
+
+// ListSynced implements storage.ListObserver.
+// Page file is rewritten if path is set.
+func (d *Dashboard) ListSynced(sourceID, list string, items []issue.Issue) {
+	logger := d.logger.WithFields(logrus.Fields{"dashboard": d.settings.Path, "list": list})
+	now := d.now()
+	since, err := d.seen.Update(sourceID, list, items, now)
+	if err != nil {
+		logger.WithError(err).Warn("failed to save first seen times")
+	}
+	cards := make([]Card, len(items))
+	for i, item := range items {
+		cards[i] = Card{
+			Title:  item.Title(),
+			URL:    item.URL(),
+			Repo:   item.Repo(),
+			Source: sourceID,
+			Since:  since[i],
+		}
+	}
+
+	d.mu.Lock()
+	sources, ok := d.lists[list]
+	if !ok {
+		sources = make(map[string][]Card)
+		d.lists[list] = sources
+		d.order = append(d.order, list)
+	}
+	sources[sourceID] = cards
+	d.updated = now
+
+	var buf bytes.Buffer
+	err = d.render(&buf)
+	d.mu.Unlock()
+
+	if d.settings.Path == "" {
+		return
+	}
+	if err == nil {
+		// web servers never serve partial page
+		err = fsutil.WriteFile(d.settings.Path, buf.Bytes(), 0o644)
+	}
+	if err != nil {
+		logger.WithError(err).Error("failed to write dashboard")
+	}
+}
+
+// Columns returns current lists.
+func (d *Dashboard) Columns() []Column {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.columns()
+}
+
+func (d *Dashboard) columns() []Column {
+	columns := make([]Column, len(d.order))
+	for i, name := range d.order {
+		sourceIDs := make([]string, 0, len(d.lists[name]))
+		for sourceID := range d.lists[name] {
+			sourceIDs = append(sourceIDs, sourceID)
+		}
+		sort.Strings(sourceIDs)
+		columns[i] = Column{Name: name, Cards: make([]Card, 0)}
+		for _, sourceID := range sourceIDs {
+			columns[i].Cards = append(columns[i].Cards, d.lists[name][sourceID]...)
+		}
+	}
+	return columns
+}
+
+// render writes HTML page, must be called with lock held.
+func (d *Dashboard) render(w io.Writer) error {
+	return pageTemplate.Execute(w, page{
+		Title:   d.settings.title(),
+		Refresh: d.settings.refreshSeconds(),
+		Updated: d.updated,
+		Columns: d.columns(),
+	})
+}
+
+// ServeHTTP renders the page.
+func (d *Dashboard) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var buf bytes.Buffer
+	d.mu.Lock()
+	err := d.render(&buf)
+	d.mu.Unlock()
+	if err != nil {
+		d.logger.WithError(err).Error("failed to render dashboard")
+		http.Error(w, "failed to render dashboard", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		d.logger.WithError(err).Debug("failed to send dashboard")
+	}
+}
+
+// age formats time item has been in the list.
+func age(now, since time.Time) string {
+	d := now.Sub(since)
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,112 @@
+package dashboard
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestDashboard(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Dashboard")
+}
+
+type testIssue struct {
+	title string
+	url   string
+	repo  string
+}
+
+func (i testIssue) Title() string { return i.title }
+func (i testIssue) URL() string   { return i.url }
+func (i testIssue) Repo() string  { return i.repo }
+
+var _ = Describe("Dashboard", func() {
+	var (
+		d   *Dashboard
+		now time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		dir := GinkgoT().TempDir()
+		var err error
+		d, err = New(&Settings{Path: filepath.Join(dir, "index.html"), StatePath: filepath.Join(dir, "state.json")}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		d.now = func() time.Time { return now }
+	})
+
+	It("merges lists of all sources", func() {
+		fix := testIssue{title: "Fix", url: "https://github.com/o/r/pull/1", repo: "o/r"}
+		d.ListSynced("github", "To review", []issue.Issue{fix})
+		d.ListSynced("jira", "Assigned", []issue.Issue{testIssue{title: "Bug"}})
+		now = now.Add(2 * time.Hour)
+		d.ListSynced("gitea", "To review", []issue.Issue{testIssue{title: "Bump"}})
+		d.ListSynced("github", "To review", []issue.Issue{fix, testIssue{title: "Docs"}})
+
+		start := now.Add(-2 * time.Hour)
+		Expect(d.Columns()).To(Equal([]Column{
+			{Name: "To review", Cards: []Card{
+				{Title: "Bump", Source: "gitea", Since: now},
+				{Title: "Fix", URL: "https://github.com/o/r/pull/1", Repo: "o/r", Source: "github", Since: start},
+				{Title: "Docs", Source: "github", Since: now},
+			}},
+			{Name: "Assigned", Cards: []Card{
+				{Title: "Bug", Source: "jira", Since: start},
+			}},
+		}))
+
+		d.ListSynced("jira", "Assigned", nil)
+		Expect(d.Columns()[1].Cards).To(BeEmpty())
+	})
+
+	It("keeps first seen times across restarts", func() {
+		d.ListSynced("github", "To review", []issue.Issue{testIssue{title: "Fix"}})
+
+		restarted, err := New(d.settings, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		restarted.now = func() time.Time { return now.Add(time.Hour) }
+		restarted.ListSynced("github", "To review", []issue.Issue{testIssue{title: "Fix"}})
+		Expect(restarted.Columns()[0].Cards[0].Since).To(BeTemporally("==", now))
+	})
+
+	It("writes and serves the page", func() {
+		d.ListSynced("github", "To review", []issue.Issue{testIssue{title: "<script>", url: "https://github.com/o/r/pull/1", repo: "o/r"}})
+		data, err := os.ReadFile(d.settings.Path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring(`<a href="https://github.com/o/r/pull/1">&lt;script&gt;</a>`))
+		Expect(string(data)).To(ContainSubstring(`<span class="badge">o/r</span>`))
+		Expect(string(data)).To(ContainSubstring(`<meta http-equiv="refresh" content="60">`))
+		Expect(string(data)).To(MatchRegexp(`<span title="In list since [0-9-]+ [0-9:]+">in list 0m</span>`))
+
+		srv := httptest.NewServer(d)
+		defer srv.Close()
+		resp, err := http.Get(srv.URL) //nolint:noctx
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.Header.Get("Content-Type")).To(Equal("text/html; charset=utf-8"))
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(Equal(string(data)))
+	})
+
+	DescribeTable("age",
+		func(d time.Duration, expected string) {
+			now := time.Now()
+			Expect(age(now, now.Add(-d))).To(Equal(expected))
+		},
+		Entry("minutes", 5*time.Minute, "5m"),
+		Entry("hours", 3*time.Hour+20*time.Minute, "3h"),
+		Entry("days", 50*time.Hour, "2d"),
+	)
+})
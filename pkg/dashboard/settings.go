@@ -0,0 +1,34 @@
+package dashboard
+
+// DefaultTitle is a page title.
+const DefaultTitle = "todohub"
+
+// DefaultRefreshSeconds sets how often the page reloads itself.
+const DefaultRefreshSeconds = 60
+
+// Settings holds dashboard settings.
+// The page is served on /dashboard when server is enabled.
+type Settings struct {
+	// Path is an optional HTML file rewritten after each list sync.
+	Path  string `yaml:"path,omitempty"`
+	Title string `yaml:"title,omitempty"`
+	// RefreshSeconds sets page auto-reload interval, defaults to 60.
+	RefreshSeconds int `yaml:"refresh_seconds,omitempty"`
+	// StatePath is an optional JSON file keeping times items were first seen,
+	// so that item ages survive restarts.
+	StatePath string `yaml:"state_path,omitempty"`
+}
+
+func (s Settings) title() string {
+	if s.Title == "" {
+		return DefaultTitle
+	}
+	return s.Title
+}
+
+func (s Settings) refreshSeconds() int {
+	if s.RefreshSeconds == 0 {
+		return DefaultRefreshSeconds
+	}
+	return s.RefreshSeconds
+}
@@ -0,0 +1,53 @@
+package dashboard
+
+import "html/template"
+
+var pageTemplate = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"age": age,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="{{ .Refresh }}">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: sans-serif; margin: 0; background: #f4f5f7; color: #172b4d; }
+header { padding: 8px 16px; display: flex; justify-content: space-between; align-items: baseline; }
+h1 { font-size: 1.4em; margin: 0; }
+main { display: flex; gap: 12px; padding: 0 16px 16px; overflow-x: auto; align-items: flex-start; }
+section { background: #ebecf0; border-radius: 6px; padding: 8px; min-width: 260px; max-width: 320px; flex: 0 0 auto; }
+h2 { font-size: 1em; margin: 4px 4px 8px; }
+.count { color: #5e6c84; font-weight: normal; }
+.card { background: #fff; border-radius: 4px; padding: 8px; margin-bottom: 8px; box-shadow: 0 1px 0 rgba(9, 30, 66, .25); }
+.card a { color: inherit; text-decoration: none; }
+.card a:hover { text-decoration: underline; }
+.meta { margin-top: 6px; font-size: .8em; color: #5e6c84; display: flex; gap: 6px; flex-wrap: wrap; align-items: center; }
+.badge { background: #dfe1e6; border-radius: 3px; padding: 1px 6px; color: #172b4d; }
+.source { text-transform: uppercase; font-size: .9em; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{ .Title }}</h1>
+<small>Updated {{ .Updated.Format "2006-01-02 15:04:05 MST" }}</small>
+</header>
+<main>
+{{- range .Columns }}
+<section>
+<h2>{{ .Name }} <span class="count">{{ len .Cards }}</span></h2>
+{{- range .Cards }}
+<div class="card">
+{{- if .URL }}<a href="{{ .URL }}">{{ .Title }}</a>{{ else }}{{ .Title }}{{ end }}
+<div class="meta">
+{{- if .Repo }}<span class="badge">{{ .Repo }}</span>{{ end }}
+<span class="source">{{ .Source }}</span>
+<span title="In list since {{ .Since.Format "2006-01-02 15:04" }}">in list {{ age $.Updated .Since }}</span>
+</div>
+</div>
+{{- end }}
+</section>
+{{- end }}
+</main>
+</body>
+</html>
+`))
@@ -0,0 +1,40 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestFsutil(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Fsutil")
+}
+
+var _ = Describe("WriteFile", func() {
+	It("replaces file content and mode", func() {
+		dir := GinkgoT().TempDir()
+		path := filepath.Join(dir, "index.html")
+		Expect(os.WriteFile(path, []byte("old"), 0o600)).To(Succeed())
+
+		Expect(WriteFile(path, []byte("new"), 0o644)).To(Succeed())
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("new"))
+		info, err := os.Stat(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o644)))
+
+		// No temporary files are left behind
+		entries, err := os.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+	})
+
+	It("fails if directory is missing", func() {
+		Expect(WriteFile(filepath.Join(GinkgoT().TempDir(), "missing", "index.html"), nil, 0o644)).NotTo(Succeed())
+	})
+})
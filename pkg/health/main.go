@@ -0,0 +1,165 @@
+package health
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultIntervals is a number of sync intervals a source may miss before it's considered unhealthy.
+const DefaultIntervals = 3
+
+// SyncFunc runs source sync.
+type SyncFunc func(description string) error
+
+// syncState holds information about source syncs.
+type syncState struct {
+	running     bool
+	started     time.Time
+	lastSuccess time.Time
+	lastErr     error
+}
+
+// Tracker records storage connection and sync results for liveness and readiness checks.
+type Tracker struct {
+	mu               sync.Mutex
+	interval         time.Duration
+	intervals        int
+	storageConnected bool
+	sources          map[string]*syncState
+	now              func() time.Time
+}
+
+// New returns tracker for the sync interval.
+// Sources are expected to succeed at least once every `intervals` sync intervals.
+func New(interval time.Duration, intervals int) *Tracker {
+	if intervals <= 0 {
+		intervals = DefaultIntervals
+	}
+	return &Tracker{
+		interval:  interval,
+		intervals: intervals,
+		sources:   make(map[string]*syncState),
+		now:       time.Now,
+	}
+}
+
+// StorageConnected marks storage as connected.
+func (t *Tracker) StorageConnected() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.storageConnected = true
+}
+
+// Track registers a source and wraps its sync function to record results.
+func (t *Tracker) Track(source string, syncFunc SyncFunc) SyncFunc {
+	t.mu.Lock()
+	t.sources[source] = &syncState{}
+	t.mu.Unlock()
+
+	return func(description string) error {
+		t.mu.Lock()
+		state := t.sources[source]
+		state.running = true
+		state.started = t.now()
+		t.mu.Unlock()
+
+		err := syncFunc(description)
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		state.running = false
+		state.lastErr = err
+		if err == nil {
+			state.lastSuccess = t.now()
+		}
+		return err
+	}
+}
+
+// deadline returns max allowed time between successful syncs.
+func (t *Tracker) deadline() time.Duration {
+	return t.interval * time.Duration(t.intervals)
+}
+
+// Live returns an error if a sync is stuck for longer than allowed.
+func (t *Tracker) Live() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var problems []string
+	for _, name := range t.sourceNames() {
+		state := t.sources[name]
+		if state.running && t.now().Sub(state.started) > t.deadline() {
+			problems = append(problems, fmt.Sprintf("%s: sync running since %s", name, state.started.Format(time.RFC3339)))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Ready returns an error if storage is not connected or a source didn't sync successfully recently.
+func (t *Tracker) Ready() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var problems []string
+	if !t.storageConnected {
+		problems = append(problems, "storage not connected")
+	}
+	for _, name := range t.sourceNames() {
+		state := t.sources[name]
+		switch {
+		case state.lastSuccess.IsZero():
+			problems = append(problems, fmt.Sprintf("%s: no successful sync yet", name))
+		case t.now().Sub(state.lastSuccess) > t.deadline():
+			msg := fmt.Sprintf("%s: last successful sync at %s", name, state.lastSuccess.Format(time.RFC3339))
+			if state.lastErr != nil {
+				msg = fmt.Sprintf("%s: %v", msg, state.lastErr)
+			}
+			problems = append(problems, msg)
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// sourceNames returns sorted list of tracked sources.
+func (t *Tracker) sourceNames() []string {
+	names := make([]string, 0, len(t.sources))
+	for name := range t.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LivenessHandler serves liveness probe.
+func (t *Tracker) LivenessHandler() http.Handler {
+	return checkHandler(t.Live)
+}
+
+// ReadinessHandler serves readiness probe.
+func (t *Tracker) ReadinessHandler() http.Handler {
+	return checkHandler(t.Ready)
+}
+
+func checkHandler(check func() error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if err := check(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, err.Error())
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+}
@@ -0,0 +1,84 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestHealth(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Health")
+}
+
+var errSync = errors.New("sync failed")
+
+var _ = Describe("Tracker", func() {
+	var (
+		tracker *Tracker
+		now     time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		tracker = New(time.Minute, 3)
+		tracker.now = func() time.Time { return now }
+	})
+
+	It("is not ready until storage is connected and sources synced", func() {
+		sync := tracker.Track("github", func(string) error { return nil })
+		Expect(tracker.Ready()).To(MatchError("storage not connected; github: no successful sync yet"))
+
+		tracker.StorageConnected()
+		Expect(sync("test")).To(Succeed())
+		Expect(tracker.Ready()).To(Succeed())
+	})
+
+	It("stops being ready when syncs fail for too long", func() {
+		tracker.StorageConnected()
+		fail := false
+		sync := tracker.Track("jira", func(string) error {
+			if fail {
+				return errSync
+			}
+			return nil
+		})
+		Expect(sync("test")).To(Succeed())
+
+		fail = true
+		now = now.Add(2 * time.Minute)
+		Expect(sync("test")).To(MatchError(errSync))
+		Expect(tracker.Ready()).To(Succeed())
+
+		now = now.Add(2 * time.Minute)
+		Expect(sync("test")).To(MatchError(errSync))
+		Expect(tracker.Ready()).To(MatchError(ContainSubstring("sync failed")))
+	})
+
+	It("is not live when sync is stuck", func() {
+		sync := tracker.Track("github", func(string) error {
+			now = now.Add(5 * time.Minute)
+			Expect(tracker.Live()).To(MatchError(ContainSubstring("github: sync running since")))
+			return nil
+		})
+		Expect(tracker.Live()).To(Succeed())
+		Expect(sync("test")).To(Succeed())
+		Expect(tracker.Live()).To(Succeed())
+	})
+
+	It("serves probes", func() {
+		rec := httptest.NewRecorder()
+		tracker.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", http.NoBody))
+		Expect(rec.Code).To(Equal(http.StatusServiceUnavailable))
+
+		rec = httptest.NewRecorder()
+		tracker.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", http.NoBody))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal("ok\n"))
+	})
+})
@@ -0,0 +1,302 @@
+// Package ical reads and writes iCalendar (RFC 5545) components.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// TimeFormat is UTC date-time format.
+const TimeFormat = "20060102T150405Z"
+
+// DateFormat is date format.
+const DateFormat = "20060102"
+
+// lineLength is a maximum line length in octets before folding.
+const lineLength = 75
+
+// Property is a content line. Params and Value are kept as written.
+type Property struct {
+	Name   string
+	Params string
+	Value  string
+}
+
+// Component is a calendar component, e.g. VCALENDAR or VTODO.
+// Unknown properties are kept to survive round trips.
+type Component struct {
+	Name       string
+	Properties []Property
+	Children   []*Component
+}
+
+// NewCalendar returns VCALENDAR with required properties.
+func NewCalendar(prodID string) *Component {
+	c := &Component{Name: "VCALENDAR"}
+	c.SetRaw("VERSION", "2.0")
+	c.SetRaw("PRODID", prodID)
+	return c
+}
+
+// Get returns unescaped text value of the first property with this name.
+func (c *Component) Get(name string) string {
+	for _, p := range c.Properties {
+		if strings.EqualFold(p.Name, name) {
+			return Unescape(p.Value)
+		}
+	}
+	return ""
+}
+
+// GetList returns unescaped comma-separated values of all properties with this name.
+func (c *Component) GetList(name string) []string {
+	values := make([]string, 0)
+	for _, p := range c.Properties {
+		if !strings.EqualFold(p.Name, name) || p.Value == "" {
+			continue
+		}
+		for _, v := range splitList(p.Value) {
+			values = append(values, Unescape(v))
+		}
+	}
+	return values
+}
+
+// Set replaces property with escaped text value.
+func (c *Component) Set(name, value string) {
+	c.SetRaw(name, Escape(value))
+}
+
+// SetList replaces property with escaped comma-separated values.
+func (c *Component) SetList(name string, values []string) {
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		escaped[i] = Escape(v)
+	}
+	c.SetRaw(name, strings.Join(escaped, ","))
+}
+
+// SetTime replaces property with UTC date-time value.
+func (c *Component) SetTime(name string, t time.Time) {
+	c.SetRaw(name, t.UTC().Format(TimeFormat))
+}
+
+// SetDate replaces property with date value.
+func (c *Component) SetDate(name string, t time.Time) {
+	c.Remove(name)
+	c.Properties = append(c.Properties, Property{Name: name, Params: "VALUE=DATE", Value: t.Format(DateFormat)})
+}
+
+// SetRaw replaces all properties with this name with a single value.
+func (c *Component) SetRaw(name, value string) {
+	c.Remove(name)
+	c.Properties = append(c.Properties, Property{Name: name, Value: value})
+}
+
+// Remove drops all properties with this name.
+func (c *Component) Remove(name string) {
+	props := make([]Property, 0, len(c.Properties))
+	for _, p := range c.Properties {
+		if !strings.EqualFold(p.Name, name) {
+			props = append(props, p)
+		}
+	}
+	c.Properties = props
+}
+
+// Find returns all nested components with this name.
+func (c *Component) Find(name string) []*Component {
+	results := make([]*Component, 0)
+	for _, child := range c.Children {
+		if strings.EqualFold(child.Name, name) {
+			results = append(results, child)
+		}
+		results = append(results, child.Find(name)...)
+	}
+	return results
+}
+
+// Encode writes component with CRLF line endings and folded lines.
+func (c *Component) Encode(w io.Writer) error {
+	_, err := io.WriteString(w, c.String())
+	return err
+}
+
+// String returns encoded component.
+func (c *Component) String() string {
+	var b strings.Builder
+	c.encode(&b)
+	return b.String()
+}
+
+func (c *Component) encode(b *strings.Builder) {
+	writeLine(b, "BEGIN:"+c.Name)
+	for _, p := range c.Properties {
+		line := p.Name
+		if p.Params != "" {
+			line += ";" + p.Params
+		}
+		writeLine(b, line+":"+p.Value)
+	}
+	for _, child := range c.Children {
+		child.encode(b)
+	}
+	writeLine(b, "END:"+c.Name)
+}
+
+// writeLine folds line at 75 octets without splitting UTF-8 characters.
+func writeLine(b *strings.Builder, line string) {
+	limit := lineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// continuation lines start with a space
+		limit = lineLength - 1
+	}
+	b.WriteString(line + "\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// Decode reads a single top-level component.
+func Decode(r io.Reader) (*Component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	var stack []*Component
+	var root *Component
+	for _, line := range lines {
+		p, err := parseLine(line)
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToUpper(p.Name) {
+		case "BEGIN":
+			c := &Component{Name: strings.ToUpper(p.Value)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, c)
+			} else if root == nil {
+				root = c
+			}
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 0 || !strings.EqualFold(stack[len(stack)-1].Name, p.Value) {
+				return nil, fmt.Errorf("ical: unexpected END:%s", p.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("ical: property %s outside of component", p.Name)
+			}
+			c := stack[len(stack)-1]
+			c.Properties = append(c.Properties, p)
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("ical: no component found")
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("ical: missing END:%s", stack[len(stack)-1].Name)
+	}
+	return root, nil
+}
+
+// unfold joins continuation lines.
+func unfold(r io.Reader) ([]string, error) {
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseLine splits content line into name, params and value.
+// Colons in quoted parameter values are skipped.
+func parseLine(line string) (Property, error) {
+	quoted := false
+	for i, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ':' && !quoted:
+			name, params, _ := strings.Cut(line[:i], ";")
+			return Property{Name: strings.ToUpper(name), Params: params, Value: line[i+1:]}, nil
+		}
+	}
+	return Property{}, fmt.Errorf("ical: invalid content line %q", line)
+}
+
+// Escape escapes TEXT value.
+func Escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// Unescape reverts TEXT value escaping.
+func Unescape(s string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		if !escaped {
+			if r == '\\' {
+				escaped = true
+				continue
+			}
+			b.WriteRune(r)
+			continue
+		}
+		escaped = false
+		switch r {
+		case 'n', 'N':
+			b.WriteRune('\n')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// splitList splits value on unescaped commas.
+func splitList(s string) []string {
+	values := make([]string, 0)
+	start := 0
+	escaped := false
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ',':
+			values = append(values, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(values, s[start:])
+}
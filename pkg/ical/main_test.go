@@ -0,0 +1,73 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestIcal(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "iCalendar")
+}
+
+var _ = DescribeTable("Escape",
+	func(s, expected string) {
+		Expect(Escape(s)).To(Equal(expected))
+		Expect(Unescape(expected)).To(Equal(s))
+	},
+	Entry("Plain", "Fix bug", "Fix bug"),
+	Entry("Special characters", `a,b;c\d`, `a\,b\;c\\d`),
+	Entry("Newline", "line1\nline2", `line1\nline2`),
+)
+
+var _ = Describe("Component", func() {
+	It("folds long lines without splitting characters", func() {
+		c := &Component{Name: "VTODO"}
+		c.Set("SUMMARY", strings.Repeat("ж", 60))
+		encoded := c.String()
+		for _, line := range strings.Split(strings.TrimSuffix(encoded, "\r\n"), "\r\n") {
+			Expect(len(line)).To(BeNumerically("<=", lineLength))
+		}
+
+		decoded, err := Decode(strings.NewReader(encoded))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded.Get("SUMMARY")).To(Equal(strings.Repeat("ж", 60)))
+	})
+
+	It("round trips nested components keeping unknown properties", func() {
+		cal := NewCalendar("-//todohub//EN")
+		todo := &Component{Name: "VTODO"}
+		todo.Set("UID", "1@todohub")
+		todo.SetList("CATEGORIES", []string{"vrutkovs/todohub", "a,b"})
+		todo.SetTime("DTSTAMP", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+		todo.Properties = append(todo.Properties, Property{Name: "X-APPLE-SORT-ORDER", Params: `X-P="a:b"`, Value: "1"})
+		cal.Children = append(cal.Children, todo)
+
+		decoded, err := Decode(strings.NewReader(cal.String()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded.Get("PRODID")).To(Equal("-//todohub//EN"))
+		todos := decoded.Find("VTODO")
+		Expect(todos).To(HaveLen(1))
+		Expect(todos[0].Get("UID")).To(Equal("1@todohub"))
+		Expect(todos[0].GetList("CATEGORIES")).To(Equal([]string{"vrutkovs/todohub", "a,b"}))
+		Expect(todos[0].Get("DTSTAMP")).To(Equal("20240102T030405Z"))
+		Expect(todos[0].Properties).To(ContainElement(Property{Name: "X-APPLE-SORT-ORDER", Params: `X-P="a:b"`, Value: "1"}))
+		Expect(decoded.String()).To(Equal(cal.String()))
+	})
+
+	It("writes dates", func() {
+		todo := &Component{Name: "VTODO"}
+		todo.SetTime("DUE", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+		todo.SetDate("DUE", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+		Expect(todo.String()).To(Equal("BEGIN:VTODO\r\nDUE;VALUE=DATE:20240102\r\nEND:VTODO\r\n"))
+	})
+
+	It("rejects unbalanced components", func() {
+		_, err := Decode(strings.NewReader("BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nEND:VCALENDAR\r\n"))
+		Expect(err).To(MatchError(ContainSubstring("unexpected END:VCALENDAR")))
+	})
+})
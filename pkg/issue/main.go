@@ -3,6 +3,7 @@ package issue
 import (
 	"crypto/sha256"
 	"fmt"
+	"time"
 )
 
 // Issue represents an issue in search query.
@@ -12,6 +13,57 @@ type Issue interface {
 	Repo() string
 }
 
+// Keyed is implemented by issues which have a stable identifier.
+// Keyed items are matched by key during sync, so items sharing a title are kept
+// apart and renamed items keep their cards, if storage items are keyed too.
+type Keyed interface {
+	Key() string
+}
+
+// Key returns a stable issue identifier, falling back to URL.
+func Key(i Issue) string {
+	if k, ok := i.(Keyed); ok && k.Key() != "" {
+		return k.Key()
+	}
+	return i.URL()
+}
+
+// Matches returns true if stored item with the key and title refers to the issue.
+// Source keys are compared if both are known, titles otherwise.
+func Matches(i Issue, key, title string) bool {
+	if k, ok := i.(Keyed); ok && k.Key() != "" && key != "" {
+		return k.Key() == key
+	}
+	return i.Title() == title
+}
+
+// Described is implemented by issues which carry extra details for the card.
+type Described interface {
+	Description() string
+}
+
+// Description returns issue details or an empty string.
+func Description(i Issue) string {
+	if d, ok := i.(Described); ok {
+		return d.Description()
+	}
+	return ""
+}
+
+// Scheduled is implemented by issues which have a due date.
+type Scheduled interface {
+	Due() time.Time
+}
+
+// Due returns issue due date or zero time.
+// Dates without time of day are UTC midnight.
+func Due(i Issue) time.Time {
+	if s, ok := i.(Scheduled); ok {
+		return s.Due()
+	}
+	return time.Time{}
+}
+
 // List represents a list of issues.
 type List struct {
 	Issues []Issue
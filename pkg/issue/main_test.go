@@ -2,6 +2,7 @@ package issue
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -30,6 +31,51 @@ func (i IssueMock) Repo() string {
 	return i.repo
 }
 
+type KeyedIssueMock struct {
+	IssueMock
+	key string
+}
+
+func (i KeyedIssueMock) Key() string {
+	return i.key
+}
+
+var _ = DescribeTable("Key",
+	func(i Issue, expected string) {
+		Expect(Key(i)).To(Equal(expected))
+	},
+	Entry("URL", IssueMock{url: "https://example.com"}, "https://example.com"),
+	Entry("Keyed", KeyedIssueMock{IssueMock{url: "https://example.com"}, "example#1"}, "example#1"),
+	Entry("Empty key", KeyedIssueMock{IssueMock{url: "https://example.com"}, ""}, "https://example.com"),
+)
+
+var _ = DescribeTable("Matches",
+	func(i Issue, key, title string, expected bool) {
+		Expect(Matches(i, key, title)).To(Equal(expected))
+	},
+	Entry("Same key", KeyedIssueMock{IssueMock{title: "New"}, "example#1"}, "example#1", "Old", true),
+	Entry("Other key", KeyedIssueMock{IssueMock{title: "Same"}, "example#1"}, "example#2", "Same", false),
+	Entry("Stored key unknown", KeyedIssueMock{IssueMock{title: "Same"}, "example#1"}, "", "Same", true),
+	Entry("Not keyed", IssueMock{title: "Same"}, "example#1", "Same", true),
+)
+
+type ScheduledIssueMock struct {
+	IssueMock
+	due time.Time
+}
+
+func (i ScheduledIssueMock) Due() time.Time {
+	return i.due
+}
+
+var _ = DescribeTable("Due",
+	func(i Issue, expected time.Time) {
+		Expect(Due(i)).To(Equal(expected))
+	},
+	Entry("Not scheduled", IssueMock{}, time.Time{}),
+	Entry("Scheduled", ScheduledIssueMock{due: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)),
+)
+
 var _ = Describe("Issue List", func() {
 	issueA := IssueMock{
 		title: "issue A",
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "todohub"
+
+var (
+	// SyncDuration tracks time spent syncing a single list.
+	SyncDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "sync_duration_seconds",
+		Help:      "Time spent syncing a list from a source to storage.",
+		Buckets:   prometheus.ExponentialBuckets(0.5, 2, 10),
+	}, []string{"source", "list"})
+
+	// ItemsFetched holds the number of items returned by the last search, after source filters.
+	ItemsFetched = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "items_fetched",
+		Help:      "Number of items returned by the last source search, after source filters.",
+	}, []string{"source", "list"})
+
+	// CardsCreated counts cards added to storage.
+	CardsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cards_created_total",
+		Help:      "Number of cards created in storage.",
+	}, []string{"source", "list"})
+
+	// CardsDeleted counts cards removed from storage.
+	CardsDeleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cards_deleted_total",
+		Help:      "Number of cards removed from storage.",
+	}, []string{"source", "list"})
+
+	// APIErrors counts failed calls to source and storage APIs.
+	APIErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "api_errors_total",
+		Help:      "Number of failed API calls by backend.",
+	}, []string{"backend"})
+
+	// GithubRateLimitRemaining holds remaining GitHub API requests in the current window.
+	GithubRateLimitRemaining = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "github_rate_limit_remaining",
+		Help:      "Remaining GitHub API requests in the current rate-limit window.",
+	})
+
+	// LastSuccessfulSync holds unix time of the last sync without errors.
+	LastSuccessfulSync = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_successful_sync_timestamp_seconds",
+		Help:      "Unix timestamp of the last sync which completed without errors.",
+	}, []string{"source"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		SyncDuration,
+		ItemsFetched,
+		CardsCreated,
+		CardsDeleted,
+		APIErrors,
+		GithubRateLimitRemaining,
+		LastSuccessfulSync,
+	)
+}
+
+// ObserveSync records list sync duration since start.
+func ObserveSync(source, list string, start time.Time) {
+	SyncDuration.WithLabelValues(source, list).Observe(time.Since(start).Seconds())
+}
+
+// SyncSucceeded records the time of a successful sync.
+func SyncSucceeded(source string) {
+	LastSuccessfulSync.WithLabelValues(source).SetToCurrentTime()
+}
+
+// APIError increments error counter for the backend.
+func APIError(backend string) {
+	APIErrors.WithLabelValues(backend).Inc()
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+)
+
+// ReadHeaderTimeout limits time to read request headers.
+const ReadHeaderTimeout = 10 * time.Second
+
+// Server exposes metrics and other daemon endpoints over HTTP.
+type Server struct {
+	mux      *http.ServeMux
+	settings *Settings
+	logger   *logrus.Logger
+}
+
+// New returns HTTP server with metrics endpoint registered.
+func New(s *Settings, logger *logrus.Logger) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	return &Server{
+		mux:      mux,
+		settings: s,
+		logger:   logger,
+	}
+}
+
+// Handle registers a handler for the given pattern.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Start runs HTTP listener in background.
+func (s *Server) Start() {
+	srv := &http.Server{
+		Addr:              s.settings.Listen,
+		Handler:           s.mux,
+		ReadHeaderTimeout: ReadHeaderTimeout,
+	}
+	logger := s.logger.WithField("listen", s.settings.Listen)
+	logger.Info("starting http server")
+	go func() {
+		if err := srv.ListenAndServe(); err != nil {
+			logger.Fatal(err)
+		}
+	}()
+}
@@ -0,0 +1,8 @@
+package server
+
+// Settings holds HTTP listener settings.
+type Settings struct {
+	Listen string `yaml:"listen"`
+	// HealthIntervals is a number of sync intervals a source may miss before readiness fails.
+	HealthIntervals int `yaml:"health_intervals,omitempty"`
+}
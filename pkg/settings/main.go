@@ -1,14 +1,31 @@
 package settings
 
 import (
-	"fmt"
+	"errors"
 
 	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/calendar"
+	"github.com/vrutkovs/todohub/pkg/dashboard"
+	"github.com/vrutkovs/todohub/pkg/server"
+	"github.com/vrutkovs/todohub/pkg/source/bitbucket"
+	"github.com/vrutkovs/todohub/pkg/source/bugzilla"
+	"github.com/vrutkovs/todohub/pkg/source/feed"
+	"github.com/vrutkovs/todohub/pkg/source/gerrit"
+	"github.com/vrutkovs/todohub/pkg/source/gitea"
 	"github.com/vrutkovs/todohub/pkg/source/github"
 	"github.com/vrutkovs/todohub/pkg/source/jira"
 	"github.com/vrutkovs/todohub/pkg/storage"
+	"github.com/vrutkovs/todohub/pkg/storage/caldav"
+	"github.com/vrutkovs/todohub/pkg/storage/deck"
+	"github.com/vrutkovs/todohub/pkg/storage/export"
+	"github.com/vrutkovs/todohub/pkg/storage/githubprojects"
+	jirastorage "github.com/vrutkovs/todohub/pkg/storage/jira"
+	"github.com/vrutkovs/todohub/pkg/storage/kanboard"
+	"github.com/vrutkovs/todohub/pkg/storage/org"
+	"github.com/vrutkovs/todohub/pkg/storage/taskwarrior"
 	"github.com/vrutkovs/todohub/pkg/storage/todoist"
 	"github.com/vrutkovs/todohub/pkg/storage/trello"
+	"github.com/vrutkovs/todohub/pkg/storage/vikunja"
 	"gopkg.in/yaml.v2"
 )
 
@@ -17,21 +34,41 @@ const DefaultSyncTimeoutMinutes = 5
 
 // Settings holds app-level settings.
 type Settings struct {
-	Storage     StorageSettings `yaml:"storage"`
-	Source      SourceSettings  `yaml:"source"`
-	SyncTimeout uint64          `yaml:"sync_timeout"`
+	Storage StorageSettings  `yaml:"storage"`
+	Source  SourceSettings   `yaml:"source"`
+	Server  *server.Settings `yaml:"server,omitempty"`
+	// Dashboard renders all lists as a single HTML page.
+	Dashboard *dashboard.Settings `yaml:"dashboard,omitempty"`
+	// Calendar exposes items as iCalendar feed.
+	Calendar    *calendar.Settings `yaml:"calendar,omitempty"`
+	SyncTimeout uint64             `yaml:"sync_timeout"`
 }
 
 // StorageSettings holds storage configs.
 type StorageSettings struct {
-	Trello  *trello.Settings  `yaml:"trello"`
-	Todoist *todoist.Settings `yaml:"todoist"`
+	Trello         *trello.Settings         `yaml:"trello"`
+	Todoist        *todoist.Settings        `yaml:"todoist"`
+	GithubProjects *githubprojects.Settings `yaml:"github_projects"`
+	Taskwarrior    *taskwarrior.Settings    `yaml:"taskwarrior"`
+	Caldav         *caldav.Settings         `yaml:"caldav"`
+	Org            *org.Settings            `yaml:"org"`
+	Vikunja        *vikunja.Settings        `yaml:"vikunja"`
+	Deck           *deck.Settings           `yaml:"deck"`
+	Kanboard       *kanboard.Settings       `yaml:"kanboard"`
+	Jira           *jirastorage.Settings    `yaml:"jira"`
+	// Export records changes alongside other storage, or is used alone.
+	Export *export.Settings `yaml:"export"`
 }
 
 // SourceSettings holds client configs.
 type SourceSettings struct {
-	Github *github.Settings `yaml:"github"`
-	Jira   *jira.Settings   `yaml:"jira"`
+	Github    *github.Settings    `yaml:"github"`
+	Jira      *jira.Settings      `yaml:"jira"`
+	Gitea     *gitea.Settings     `yaml:"gitea"`
+	Bugzilla  *bugzilla.Settings  `yaml:"bugzilla"`
+	Gerrit    *gerrit.Settings    `yaml:"gerrit"`
+	Feed      *feed.Settings      `yaml:"feed"`
+	Bitbucket *bitbucket.Settings `yaml:"bitbucket"`
 }
 
 // ReadFile is a function to read file and output a slice of bytes.
@@ -56,7 +93,29 @@ func LoadSettings(path string, readFile ReadFile) (*Settings, error) {
 	return &s, nil
 }
 
+// ErrNoStorage is returned when no storage is configured.
+var ErrNoStorage = errors.New("no valid storage settings found")
+
 func (s *StorageSettings) GetActiveStorageClient(logger *logrus.Logger) (storage.Client, error) {
+	primary, err := s.getPrimaryStorageClient(logger)
+	if s.Export == nil {
+		return primary, err
+	}
+	exportClient, exportErr := export.New(s.Export, logger)
+	if errors.Is(err, ErrNoStorage) {
+		return exportClient, exportErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if exportErr != nil {
+		return nil, exportErr
+	}
+	return storage.NewMirror(primary, logger, exportClient), nil
+}
+
+// getPrimaryStorageClient returns first configured storage.
+func (s *StorageSettings) getPrimaryStorageClient(logger *logrus.Logger) (storage.Client, error) {
 	if s.Trello != nil {
 		if s.Trello.AppKey != "" && s.Trello.Token != "" && s.Trello.BoardID != "" {
 			return trello.New(s.Trello)
@@ -65,5 +124,29 @@ func (s *StorageSettings) GetActiveStorageClient(logger *logrus.Logger) (storage
 	if s.Todoist != nil {
 		return todoist.New(s.Todoist, logger)
 	}
-	return nil, fmt.Errorf("no valid storage settings found")
+	if s.GithubProjects != nil {
+		return githubprojects.New(s.GithubProjects, logger)
+	}
+	if s.Taskwarrior != nil {
+		return taskwarrior.New(s.Taskwarrior, logger)
+	}
+	if s.Caldav != nil {
+		return caldav.New(s.Caldav, logger)
+	}
+	if s.Org != nil {
+		return org.New(s.Org, logger)
+	}
+	if s.Vikunja != nil {
+		return vikunja.New(s.Vikunja, logger)
+	}
+	if s.Deck != nil {
+		return deck.New(s.Deck, logger)
+	}
+	if s.Kanboard != nil {
+		return kanboard.New(s.Kanboard, logger)
+	}
+	if s.Jira != nil {
+		return jirastorage.New(s.Jira, logger)
+	}
+	return nil, ErrNoStorage
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/vrutkovs/todohub/pkg/source/github"
+	"github.com/vrutkovs/todohub/pkg/storage/export"
 	"github.com/vrutkovs/todohub/pkg/storage/todoist"
 	"github.com/vrutkovs/todohub/pkg/storage/trello"
 
@@ -146,4 +147,7 @@ var _ = DescribeTable("GetActiveStorageClient",
 		Trello:  &trelloSettings,
 		Todoist: &todoistSettings,
 	}, errTrello401),
+	Entry("Export", StorageSettings{
+		Export: &export.Settings{},
+	}, errors.New("export: path is not set")),
 )
@@ -0,0 +1,181 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/backoff"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/metrics"
+	"github.com/vrutkovs/todohub/pkg/source"
+	"github.com/vrutkovs/todohub/pkg/storage"
+)
+
+// PageSize is a number of pull requests requested per page.
+const PageSize = 100
+
+// qualifiers maps list filter keys to dashboard API parameters.
+var qualifiers = map[string]string{
+	"role":               "role",
+	"state":              "state",
+	"participant_status": "participantStatus",
+	"order":              "order",
+}
+
+// Client holds information about bitbucket client.
+type Client struct {
+	http     *http.Client
+	endpoint *url.URL
+	lists    *source.Lists[url.Values]
+	settings *Settings
+	logger   *logrus.Logger
+}
+
+// New returns bitbucket client.
+func New(s *Settings, storageClient storage.Client, logger *logrus.Logger) (*Client, error) {
+	endpoint, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	searches := make(map[string]url.Values, len(s.SearchList))
+	for project, query := range s.SearchList {
+		params, err := parseQuery(query)
+		if err != nil {
+			return nil, fmt.Errorf("bitbucket: invalid filter for %q: %w", project, err)
+		}
+		searches[project] = params
+	}
+	c := &Client{
+		http:     backoff.NewClient(backoff.DefaultPolicy()),
+		endpoint: endpoint,
+		settings: s,
+		logger:   logger,
+	}
+	c.lists = source.NewLists("bitbucket", searches, c.pullRequests, source.ToItem, storageClient, logger)
+	return c, nil
+}
+
+// parseQuery converts "key:value" pairs to dashboard API parameters.
+// Only open pull requests are fetched unless state is set.
+func parseQuery(query string) (url.Values, error) {
+	params := url.Values{}
+	for _, field := range strings.Fields(query) {
+		key, value, ok := strings.Cut(field, ":")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("expected key:value, got %q", field)
+		}
+		param, ok := qualifiers[strings.ToLower(key)]
+		if !ok {
+			return nil, fmt.Errorf("unknown qualifier %q", key)
+		}
+		params.Set(param, strings.ToUpper(value))
+	}
+	if !params.Has("state") {
+		params.Set("state", "OPEN")
+	}
+	return params, nil
+}
+
+// Sync runs pull request searches and applies changes in storage.
+func (c *Client) Sync(description string) error {
+	return c.lists.Sync(description)
+}
+
+// pullRequest is a part of Bitbucket pull request used by todohub.
+type pullRequest struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	ToRef struct {
+		Repository struct {
+			Slug    string `json:"slug"`
+			Project struct {
+				Key string `json:"key"`
+			} `json:"project"`
+		} `json:"repository"`
+	} `json:"toRef"` //nolint:tagliatelle
+	Links struct {
+		Self []struct {
+			Href string `json:"href"`
+		} `json:"self"`
+	} `json:"links"`
+}
+
+// pullRequests fetches all pages of dashboard pull requests.
+func (c *Client) pullRequests(filter url.Values) ([]issue.Issue, error) {
+	logger := c.logger.WithFields(logrus.Fields{"source": "bitbucket", "filter": filter.Encode()})
+	params := url.Values{}
+	for key, values := range filter {
+		params[key] = values
+	}
+	params.Set("limit", strconv.Itoa(PageSize))
+
+	results := make([]issue.Issue, 0)
+	for start := 0; ; {
+		params.Set("start", strconv.Itoa(start))
+		var page struct {
+			Values        []pullRequest `json:"values"`
+			IsLastPage    bool          `json:"isLastPage"`    //nolint:tagliatelle
+			NextPageStart int           `json:"nextPageStart"` //nolint:tagliatelle
+		}
+		if err := c.get("rest/api/1.0/dashboard/pull-requests", params, &page); err != nil {
+			metrics.APIError("bitbucket")
+			logger.WithError(err).Error("search failed")
+			return nil, err
+		}
+		for _, pr := range page.Values {
+			results = append(results, c.prToIssue(pr))
+		}
+		if page.IsLastPage || len(page.Values) == 0 {
+			break
+		}
+		start = page.NextPageStart
+	}
+	logger.WithField("count", len(results)).Info("results fetched")
+	return results, nil
+}
+
+// prToIssue builds issue linking to pull request web page.
+func (c *Client) prToIssue(pr pullRequest) source.Item {
+	repo := pr.ToRef.Repository
+	prURL := c.endpoint.JoinPath("projects", repo.Project.Key, "repos", repo.Slug, "pull-requests", strconv.Itoa(pr.ID)).String()
+	if len(pr.Links.Self) > 0 && pr.Links.Self[0].Href != "" {
+		prURL = pr.Links.Self[0].Href
+	}
+	return source.NewItem(pr.Title, prURL, fmt.Sprintf("%s/%s", repo.Project.Key, repo.Slug))
+}
+
+// get sends GET request with personal access token and decodes JSON response.
+func (c *Client) get(path string, params url.Values, result interface{}) error {
+	u := c.endpoint.JoinPath(path)
+	u.RawQuery = params.Encode()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.settings.Token)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && len(apiErr.Errors) > 0 {
+			return fmt.Errorf("bitbucket: %s: %s", resp.Status, apiErr.Errors[0].Message)
+		}
+		return fmt.Errorf("bitbucket: %s %s: %s", req.Method, u.Path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,97 @@
+package bitbucket
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/source"
+	"github.com/vrutkovs/todohub/pkg/storage/storagetest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBitbucket(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Bitbucket")
+}
+
+var _ = DescribeTable("parseQuery",
+	func(query, expected, expectedErr string) {
+		params, err := parseQuery(query)
+		if expectedErr != "" {
+			Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+			return
+		}
+		Expect(err).NotTo(HaveOccurred())
+		Expect(params.Encode()).To(Equal(expected))
+	},
+	Entry("Reviewer", "role:reviewer state:open", "role=REVIEWER&state=OPEN", ""),
+	Entry("Participant status", "role:author participant_status:needs_work", "participantStatus=NEEDS_WORK&role=AUTHOR&state=OPEN", ""),
+	Entry("Merged", "role:author state:merged", "role=AUTHOR&state=MERGED", ""),
+	Entry("Empty", "", "state=OPEN", ""),
+	Entry("Unknown qualifier", "repo:foo", "", `unknown qualifier "repo"`),
+	Entry("Missing value", "role:", "", "expected key:value"),
+)
+
+var _ = Describe("Sync", func() {
+	It("creates cards for dashboard pull requests", func() {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/rest/api/1.0/dashboard/pull-requests"))
+			Expect(r.Header.Get("Authorization")).To(Equal("Bearer pat"))
+			Expect(r.URL.Query().Get("role")).To(Equal("REVIEWER"))
+			Expect(r.URL.Query().Get("limit")).To(Equal("100"))
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Query().Get("start") {
+			case "0":
+				fmt.Fprint(w, `{"values":[{"id":1,"title":"Add feature","toRef":{"repository":{"slug":"app","project":{"key":"PRJ"}}},
+					"links":{"self":[{"href":"https://bitbucket.example.com/projects/PRJ/repos/app/pull-requests/1"}]}}],
+					"isLastPage":false,"nextPageStart":1}`)
+			case "1":
+				fmt.Fprint(w, `{"values":[{"id":2,"title":"Fix bug","toRef":{"repository":{"slug":"lib","project":{"key":"PRJ"}}}}],
+					"isLastPage":true}`)
+			default:
+				Fail("unexpected page " + r.URL.Query().Get("start"))
+			}
+		}))
+		defer srv.Close()
+
+		storageClient := storagetest.New(nil)
+		c, err := New(&Settings{
+			Endpoint:   srv.URL,
+			Token:      "pat",
+			SearchList: map[string]string{"To review": "role:reviewer"},
+		}, storageClient, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Sync("test")).To(Succeed())
+
+		Expect(storageClient.Lists["To review"]).To(ConsistOf(
+			issue.Issue(source.NewItem("Add feature", "https://bitbucket.example.com/projects/PRJ/repos/app/pull-requests/1", "PRJ/app")),
+			issue.Issue(source.NewItem("Fix bug", srv.URL+"/projects/PRJ/repos/lib/pull-requests/2", "PRJ/lib")),
+		))
+	})
+
+	It("returns API error message", func() {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"errors":[{"message":"Authentication failed. Please check your credentials and try again."}]}`)
+		}))
+		defer srv.Close()
+
+		c, err := New(&Settings{
+			Endpoint:   srv.URL,
+			SearchList: map[string]string{"Mine": "role:author"},
+		}, storagetest.New(nil), logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Sync("test")).To(MatchError(ContainSubstring("Authentication failed")))
+	})
+
+	It("rejects invalid filters", func() {
+		_, err := New(&Settings{SearchList: map[string]string{"Mine": "author:me"}}, storagetest.New(nil), logrus.New())
+		Expect(err).To(MatchError(ContainSubstring(`invalid filter for "Mine"`)))
+	})
+})
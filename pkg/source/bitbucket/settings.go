@@ -0,0 +1,20 @@
+package bitbucket
+
+// Settings stores info about Bitbucket Server / Data Center connection.
+type Settings struct {
+	Endpoint string `yaml:"endpoint"`
+	// Token is a personal access token.
+	Token string `yaml:"token"`
+	// SearchList maps list names to dashboard filters,
+	// e.g. "role:reviewer participant_status:unapproved", state defaults to open.
+	SearchList map[string]string `yaml:"lists"`
+}
+
+// Implement source.Settings.
+func (s Settings) ID() string {
+	return "bitbucket"
+}
+
+func (s Settings) Searches() map[string]string {
+	return s.SearchList
+}
@@ -0,0 +1,147 @@
+package bugzilla
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/backoff"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/metrics"
+	"github.com/vrutkovs/todohub/pkg/source"
+	"github.com/vrutkovs/todohub/pkg/storage"
+)
+
+// PageSize is a number of bugs requested per page.
+const PageSize = 100
+
+var errEmptySearch = errors.New("bugzilla: either quicksearch or saved_search must be set")
+
+// Client holds information about bugzilla client.
+type Client struct {
+	http     *http.Client
+	endpoint *url.URL
+	lists    *source.Lists[Search]
+	settings *Settings
+	logger   *logrus.Logger
+}
+
+// New returns bugzilla client.
+func New(s *Settings, storageClient storage.Client, logger *logrus.Logger) (*Client, error) {
+	endpoint, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		http:     backoff.NewClient(backoff.DefaultPolicy()),
+		endpoint: endpoint,
+		settings: s,
+		logger:   logger,
+	}
+	c.lists = source.NewLists("bugzilla", s.SearchList, c.searchBugs, source.ToKeyedItem, storageClient, logger)
+	return c, nil
+}
+
+// Sync runs bug searches and applies changes in storage.
+func (c *Client) Sync(description string) error {
+	return c.lists.Sync(description)
+}
+
+// query builds bug search parameters.
+func (s Search) query() (url.Values, error) {
+	params := url.Values{}
+	switch {
+	case s.Quicksearch != "":
+		params.Set("quicksearch", s.Quicksearch)
+	case s.SavedSearch != "":
+		params.Set("savedsearch", s.SavedSearch)
+		if s.SharerID != 0 {
+			params.Set("sharer_id", strconv.Itoa(s.SharerID))
+		}
+	default:
+		return nil, errEmptySearch
+	}
+	params.Set("include_fields", "id,summary,product")
+	return params, nil
+}
+
+// apiBug is a part of Bugzilla bug used by todohub.
+type apiBug struct {
+	ID      int    `json:"id"`
+	Summary string `json:"summary"`
+	Product string `json:"product"`
+}
+
+// searchBugs fetches all pages of bug search results.
+func (c *Client) searchBugs(search Search) ([]issue.Issue, error) {
+	logger := c.logger.WithFields(logrus.Fields{"source": "bugzilla", "search": search})
+	params, err := search.query()
+	if err != nil {
+		return nil, err
+	}
+	params.Set("limit", strconv.Itoa(PageSize))
+
+	results := make([]issue.Issue, 0)
+	for offset := 0; ; offset += PageSize {
+		params.Set("offset", strconv.Itoa(offset))
+		var page struct {
+			Bugs []apiBug `json:"bugs"`
+		}
+		if err := c.get("rest/bug", params, &page); err != nil {
+			metrics.APIError("bugzilla")
+			logger.WithError(err).Error("search failed")
+			return nil, err
+		}
+		for _, bug := range page.Bugs {
+			results = append(results, c.bugToIssue(bug))
+		}
+		if len(page.Bugs) < PageSize {
+			break
+		}
+	}
+	logger.WithField("count", len(results)).Info("results fetched")
+	return results, nil
+}
+
+// bugToIssue builds issue with bug link, product as repo and key in "bugzilla:<host>#<id>" form.
+func (c *Client) bugToIssue(bug apiBug) source.KeyedItem {
+	id := strconv.Itoa(bug.ID)
+	u := c.endpoint.JoinPath("show_bug.cgi")
+	u.RawQuery = url.Values{"id": {id}}.Encode()
+	return source.NewKeyedItem(fmt.Sprintf("bugzilla:%s#%s", c.endpoint.Host, id), bug.Summary, u.String(), bug.Product)
+}
+
+// get sends authenticated GET request and decodes JSON response.
+func (c *Client) get(path string, params url.Values, result interface{}) error {
+	u := c.endpoint.JoinPath(path)
+	u.RawQuery = params.Encode()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.settings.APIKey != "" {
+		req.Header.Set("X-BUGZILLA-API-KEY", c.settings.APIKey)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("bugzilla: %s: %s", resp.Status, apiErr.Message)
+		}
+		return fmt.Errorf("bugzilla: %s %s: %s", req.Method, u.Path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
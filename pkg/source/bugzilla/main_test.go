@@ -0,0 +1,119 @@
+package bugzilla
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/source"
+	"github.com/vrutkovs/todohub/pkg/storage/storagetest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBugzilla(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Bugzilla")
+}
+
+var _ = DescribeTable("Search.query",
+	func(s Search, expected string, expectedErr error) {
+		params, err := s.query()
+		if expectedErr != nil {
+			Expect(err).To(MatchError(expectedErr))
+			return
+		}
+		Expect(err).NotTo(HaveOccurred())
+		Expect(params.Encode()).To(Equal(expected))
+	},
+	Entry("Quicksearch", Search{Quicksearch: "assigned_to:me"}, "include_fields=id%2Csummary%2Cproduct&quicksearch=assigned_to%3Ame", nil),
+	Entry("Saved search", Search{SavedSearch: "My Bugs", SharerID: 42}, "include_fields=id%2Csummary%2Cproduct&savedsearch=My+Bugs&sharer_id=42", nil),
+	Entry("Empty", Search{}, "", errEmptySearch),
+)
+
+var _ = Describe("Sync", func() {
+	It("creates cards for bugs", func() {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/rest/bug"))
+			Expect(r.Header.Get("X-BUGZILLA-API-KEY")).To(Equal("apikey"))
+			Expect(r.URL.Query().Get("quicksearch")).To(Equal("assigned_to:me"))
+			Expect(r.URL.Query().Get("offset")).To(Equal("0"))
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"bugs":[{"id":123,"summary":"Crash on start","product":"OpenShift"}]}`)
+		}))
+		defer srv.Close()
+
+		storageClient := storagetest.New(nil)
+		c, err := New(&Settings{
+			Endpoint:   srv.URL,
+			APIKey:     "apikey",
+			SearchList: map[string]Search{"Bugs": {Quicksearch: "assigned_to:me"}},
+		}, storageClient, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Sync("test")).To(Succeed())
+
+		host, err := url.Parse(srv.URL)
+		Expect(err).NotTo(HaveOccurred())
+		cards := storageClient.Lists["Bugs"]
+		Expect(cards).To(HaveLen(1))
+		Expect(cards[0].Title()).To(Equal("Crash on start"))
+		Expect(cards[0].URL()).To(Equal(srv.URL + "/show_bug.cgi?id=123"))
+		Expect(cards[0].Repo()).To(Equal("OpenShift"))
+		Expect(issue.Key(cards[0])).To(Equal("bugzilla:" + host.Host + "#123"))
+	})
+
+	It("matches cards by bug ID", func() {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"bugs":[
+				{"id":1,"summary":"Crash on start","product":"OpenShift"},
+				{"id":2,"summary":"Crash on start","product":"RHEL"},
+				{"id":3,"summary":"Crash on exit (renamed)","product":"OpenShift"}
+			]}`)
+		}))
+		defer srv.Close()
+		host, err := url.Parse(srv.URL)
+		Expect(err).NotTo(HaveOccurred())
+		key := func(id string) string {
+			return "bugzilla:" + host.Host + "#" + id
+		}
+
+		storageClient := storagetest.New(map[string][]issue.Issue{
+			"Bugs": {
+				source.NewKeyedItem(key("1"), "Crash on start", srv.URL+"/show_bug.cgi?id=1", "OpenShift"),
+				source.NewKeyedItem(key("3"), "Crash on exit", srv.URL+"/show_bug.cgi?id=3", "OpenShift"),
+			},
+		})
+		c, err := New(&Settings{
+			Endpoint:   srv.URL,
+			SearchList: map[string]Search{"Bugs": {Quicksearch: "assigned_to:me"}},
+		}, storageClient, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Sync("test")).To(Succeed())
+
+		// Bugs sharing a summary get separate cards, renamed bug keeps its card
+		Expect(storageClient.Lists["Bugs"]).To(HaveLen(3))
+		Expect(storageClient.Lists["Bugs"][1].Title()).To(Equal("Crash on exit"))
+		Expect(issue.Key(storageClient.Lists["Bugs"][2])).To(Equal(key("2")))
+	})
+
+	It("returns API error message", func() {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":true,"code":32000,"message":"The API key you specified is invalid."}`)
+		}))
+		defer srv.Close()
+
+		c, err := New(&Settings{
+			Endpoint:   srv.URL,
+			SearchList: map[string]Search{"Bugs": {SavedSearch: "Mine"}},
+		}, storagetest.New(nil), logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Sync("test")).To(MatchError(ContainSubstring("The API key you specified is invalid.")))
+	})
+})
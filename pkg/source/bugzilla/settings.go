@@ -0,0 +1,21 @@
+package bugzilla
+
+// Settings stores info about Bugzilla connection.
+type Settings struct {
+	Endpoint   string            `yaml:"endpoint"`
+	APIKey     string            `yaml:"api_key"`
+	SearchList map[string]Search `yaml:"lists"`
+}
+
+// Search holds either a quicksearch string or a saved search name.
+type Search struct {
+	Quicksearch string `yaml:"quicksearch,omitempty"`
+	SavedSearch string `yaml:"saved_search,omitempty"`
+	// SharerID is an ID of the user who shared the saved search.
+	SharerID int `yaml:"sharer_id,omitempty"`
+}
+
+// Implement source.Settings.
+func (s Settings) ID() string {
+	return "bugzilla"
+}
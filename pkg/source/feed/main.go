@@ -0,0 +1,113 @@
+package feed
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/backoff"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/metrics"
+	"github.com/vrutkovs/todohub/pkg/source"
+	"github.com/vrutkovs/todohub/pkg/storage"
+)
+
+// Client holds information about feed client.
+type Client struct {
+	http  *http.Client
+	lists *source.Lists[query]
+}
+
+// query is a feed with compiled title filter.
+type query struct {
+	feed  Feed
+	title *regexp.Regexp
+}
+
+// New returns feed client.
+func New(s *Settings, storageClient storage.Client, logger *logrus.Logger) (*Client, error) {
+	queries := make(map[string]query, len(s.SearchList))
+	for project, feed := range s.SearchList {
+		q := query{feed: feed}
+		if feed.Title != "" {
+			re, err := regexp.Compile(feed.Title)
+			if err != nil {
+				return nil, fmt.Errorf("feed: invalid title filter for %q: %w", project, err)
+			}
+			q.title = re
+		}
+		queries[project] = q
+	}
+	c := &Client{
+		http: backoff.NewClient(backoff.DefaultPolicy()),
+	}
+	c.lists = source.NewLists("feed", queries, c.entries, source.ToKeyedItem, storageClient, logger)
+	return c, nil
+}
+
+// Sync fetches feeds and applies changes in storage.
+func (c *Client) Sync(description string) error {
+	return c.lists.Sync(description)
+}
+
+// entries fetches a feed and keeps entries passing the filters.
+func (c *Client) entries(q query) ([]issue.Issue, error) {
+	entries, err := c.fetch(q.feed.URL)
+	if err != nil {
+		metrics.APIError("feed")
+		return nil, err
+	}
+	results := make([]issue.Issue, 0, len(entries))
+	for _, e := range entries {
+		if q.title != nil && !q.title.MatchString(e.Title()) {
+			continue
+		}
+		if !hasCategory(e.categories, q.feed.Categories) {
+			continue
+		}
+		results = append(results, e.KeyedItem)
+	}
+	return results, nil
+}
+
+// hasCategory returns true if any of entry categories is wanted.
+func hasCategory(categories, wanted []string) bool {
+	if len(wanted) == 0 {
+		return true
+	}
+	for _, w := range wanted {
+		for _, category := range categories {
+			if strings.EqualFold(w, category) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fetch downloads and parses the feed.
+func (c *Client) fetch(feedURL string) ([]entry, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, feedURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, */*;q=0.8")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feed: GET %s: %s", feedURL, resp.Status)
+	}
+	var doc document
+	if err := xml.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("feed: failed to parse %s: %w", feedURL, err)
+	}
+	return doc.entries(), nil
+}
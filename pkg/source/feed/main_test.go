@@ -0,0 +1,114 @@
+package feed
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/source"
+	"github.com/vrutkovs/todohub/pkg/storage/storagetest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestFeed(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Feed")
+}
+
+var _ = DescribeTable("hasCategory",
+	func(categories, wanted []string, expected bool) {
+		Expect(hasCategory(categories, wanted)).To(Equal(expected))
+	},
+	Entry("No filter", []string{"low"}, nil, true),
+	Entry("Matching", []string{"openssl", "Critical"}, []string{"critical"}, true),
+	Entry("Not matching", []string{"low"}, []string{"critical"}, false),
+	Entry("No categories", nil, []string{"critical"}, false),
+)
+
+var _ = Describe("Sync", func() {
+	var srv *httptest.Server
+
+	BeforeEach(func() {
+		srv = httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	})
+
+	AfterEach(func() {
+		srv.Close()
+	})
+
+	sync := func(feeds map[string]Feed) *storagetest.Storage {
+		storageClient := storagetest.New(nil)
+		c, err := New(&Settings{SearchList: feeds}, storageClient, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Sync("test")).To(Succeed())
+		return storageClient
+	}
+
+	It("creates cards for RSS items", func() {
+		storageClient := sync(map[string]Feed{"Advisories": {URL: srv.URL + "/rss.xml"}})
+		Expect(storageClient.Lists["Advisories"]).To(ConsistOf(
+			issue.Issue(source.NewKeyedItem("advisory-1", "CVE-2024-0001: openssl buffer overflow", "https://example.com/advisories/1", "Security advisories")),
+			issue.Issue(source.NewKeyedItem("advisory-2", "CVE-2024-0002: curl header leak", "https://example.com/advisories/2", "Security advisories")),
+			issue.Issue(source.NewKeyedItem("https://example.com/digest", "Weekly digest", "https://example.com/digest", "Security advisories")),
+		))
+	})
+
+	It("creates cards for Atom entries", func() {
+		storageClient := sync(map[string]Feed{"Releases": {URL: srv.URL + "/atom.xml"}})
+		Expect(storageClient.Lists["Releases"]).To(ConsistOf(
+			issue.Issue(source.NewKeyedItem("tag:github.com,2008:Repository/1/v1.1.0", "v1.1.0", "https://github.com/vrutkovs/todohub/releases/tag/v1.1.0", "Release notes from todohub")),
+			issue.Issue(source.NewKeyedItem("tag:github.com,2008:Repository/1/v1.1.0-rc.1", "v1.1.0-rc.1", "https://github.com/vrutkovs/todohub/releases/tag/v1.1.0-rc.1", "Release notes from todohub")),
+		))
+	})
+
+	It("filters entries by title and category", func() {
+		storageClient := sync(map[string]Feed{
+			"Critical":    {URL: srv.URL + "/rss.xml", Categories: []string{"critical"}},
+			"Curl":        {URL: srv.URL + "/rss.xml", Title: "(?i)curl"},
+			"Prereleases": {URL: srv.URL + "/atom.xml", Title: `^v\d+\.\d+\.\d+-`, Categories: []string{"prerelease"}},
+			"No matches":  {URL: srv.URL + "/atom.xml", Categories: []string{"missing"}},
+		})
+		Expect(storageClient.Lists["Critical"]).To(HaveLen(1))
+		Expect(issue.Key(storageClient.Lists["Critical"][0])).To(Equal("advisory-1"))
+		Expect(storageClient.Lists["Curl"]).To(HaveLen(1))
+		Expect(issue.Key(storageClient.Lists["Curl"][0])).To(Equal("advisory-2"))
+		Expect(storageClient.Lists["Prereleases"]).To(HaveLen(1))
+		Expect(storageClient.Lists["Prereleases"][0].Title()).To(Equal("v1.1.0-rc.1"))
+		Expect(storageClient.Lists["No matches"]).To(BeEmpty())
+	})
+
+	It("matches cards by entry GUID", func() {
+		storageClient := storagetest.New(map[string][]issue.Issue{
+			"Status": {
+				source.NewKeyedItem("incident-1", "Service degraded", "https://status.example.com/incidents/1", "Status"),
+				source.NewKeyedItem("incident-3", "Maintenance scheduled", "https://status.example.com/incidents/3", "Status"),
+				source.NewKeyedItem("incident-0", "Service degraded", "https://status.example.com/incidents/0", "Status"),
+			},
+		})
+		c, err := New(&Settings{SearchList: map[string]Feed{"Status": {URL: srv.URL + "/duplicates.xml"}}}, storageClient, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Sync("test")).To(Succeed())
+
+		// Entries sharing a title are kept apart, renamed entry keeps its card
+		keys := make([]string, 0)
+		for _, i := range storageClient.Lists["Status"] {
+			keys = append(keys, issue.Key(i))
+		}
+		Expect(keys).To(ConsistOf("incident-1", "incident-2", "incident-3"))
+	})
+
+	It("rejects invalid title filter", func() {
+		_, err := New(&Settings{SearchList: map[string]Feed{"Broken": {Title: "("}}}, storagetest.New(nil), logrus.New())
+		Expect(err).To(MatchError(ContainSubstring("invalid title filter")))
+	})
+
+	It("returns error on missing feed", func() {
+		c, err := New(&Settings{SearchList: map[string]Feed{"Missing": {URL: srv.URL + "/missing.xml"}}}, storagetest.New(nil), logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Sync("test")).To(MatchError(ContainSubstring("404")))
+	})
+})
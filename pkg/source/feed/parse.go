@@ -0,0 +1,111 @@
+package feed
+
+import (
+	"strings"
+
+	"github.com/vrutkovs/todohub/pkg/source"
+)
+
+// entry is a feed item with its categories.
+type entry struct {
+	source.KeyedItem
+	categories []string
+}
+
+// document matches RSS 2.0, RSS 1.0 (RDF) and Atom feeds.
+type document struct {
+	// Atom feed title
+	Title   string      `xml:"title"`
+	Channel rssChannel  `xml:"channel"`
+	Entries []atomEntry `xml:"entry"`
+	// RSS 1.0 keeps items outside of the channel
+	Items []rssItem `xml:"item"`
+}
+
+type rssChannel struct {
+	Title string    `xml:"title"`
+	Items []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title      string   `xml:"title"`
+	Links      []string `xml:"link"`
+	GUID       string   `xml:"guid"`
+	Categories []string `xml:"category"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// entries converts feed items to entries.
+func (d document) entries() []entry {
+	results := make([]entry, 0)
+	feedTitle := strings.TrimSpace(d.Channel.Title)
+	if feedTitle == "" {
+		feedTitle = strings.TrimSpace(d.Title)
+	}
+	items := make([]rssItem, 0, len(d.Channel.Items)+len(d.Items))
+	items = append(items, d.Channel.Items...)
+	items = append(items, d.Items...)
+	for _, item := range items {
+		link := ""
+		for _, l := range item.Links {
+			if l = strings.TrimSpace(l); l != "" {
+				link = l
+				break
+			}
+		}
+		results = append(results, newEntry(item.GUID, item.Title, link, feedTitle, item.Categories))
+	}
+	for _, e := range d.Entries {
+		categories := make([]string, len(e.Categories))
+		for i, c := range e.Categories {
+			categories[i] = c.Term
+		}
+		results = append(results, newEntry(e.ID, e.Title, e.link(), feedTitle, categories))
+	}
+	return results
+}
+
+// link returns the alternate link of Atom entry.
+func (e atomEntry) link() string {
+	for _, l := range e.Links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return strings.TrimSpace(l.Href)
+		}
+	}
+	return ""
+}
+
+// newEntry builds an entry keyed by GUID, falling back to link and title.
+// Feed title is used as entry repo.
+func newEntry(guid, title, link, feedTitle string, categories []string) entry {
+	title = strings.TrimSpace(title)
+	key := strings.TrimSpace(guid)
+	if key == "" {
+		key = link
+	}
+	if key == "" {
+		key = title
+	}
+	for i, c := range categories {
+		categories[i] = strings.TrimSpace(c)
+	}
+	return entry{
+		KeyedItem:  source.NewKeyedItem(key, title, link, feedTitle),
+		categories: categories,
+	}
+}
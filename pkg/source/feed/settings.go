@@ -0,0 +1,20 @@
+package feed
+
+// Settings stores feed lists.
+type Settings struct {
+	SearchList map[string]Feed `yaml:"lists"`
+}
+
+// Feed holds feed URL and optional entry filters.
+type Feed struct {
+	URL string `yaml:"url"`
+	// Title is a regular expression entry titles must match.
+	Title string `yaml:"title,omitempty"`
+	// Categories keeps entries having any of these categories. All entries match if empty.
+	Categories []string `yaml:"categories,omitempty"`
+}
+
+// Implement source.Settings.
+func (s Settings) ID() string {
+	return "feed"
+}
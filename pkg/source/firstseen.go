@@ -0,0 +1,103 @@
+package source
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/vrutkovs/todohub/pkg/fsutil"
+	"github.com/vrutkovs/todohub/pkg/issue"
+)
+
+// FirstSeen remembers when items were first seen in synced lists.
+// Times are kept in a JSON file if path is set, so that they survive restarts.
+type FirstSeen struct {
+	path string
+	mu   sync.Mutex
+	// times maps source and list names to item times
+	times map[string]map[string]map[string]time.Time
+}
+
+// NewFirstSeen returns tracker, previously saved times are loaded from path.
+func NewFirstSeen(path string) (*FirstSeen, error) {
+	f := &FirstSeen{
+		path:  path,
+		times: make(map[string]map[string]map[string]time.Time),
+	}
+	if path == "" {
+		return f, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &f.times); err != nil {
+		return nil, fmt.Errorf("first seen: %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// ItemID identifies item within a list.
+func ItemID(i issue.Issue) string {
+	if key := issue.Key(i); key != "" {
+		return key
+	}
+	return i.Title()
+}
+
+// Update records items of the list and returns times they were first seen in the same order.
+// Items no longer in the list are forgotten.
+func (f *FirstSeen) Update(sourceID, list string, items []issue.Issue, now time.Time) ([]time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	lists, ok := f.times[sourceID]
+	if !ok {
+		lists = make(map[string]map[string]time.Time)
+		f.times[sourceID] = lists
+	}
+	previous := lists[list]
+	current := make(map[string]time.Time, len(items))
+	since := make([]time.Time, len(items))
+	for n, i := range items {
+		id := ItemID(i)
+		t, ok := current[id]
+		if !ok {
+			t, ok = previous[id]
+		}
+		if !ok {
+			t = now
+		}
+		current[id] = t
+		since[n] = t
+	}
+	lists[list] = current
+
+	if f.path == "" || sameTimes(previous, current) {
+		return since, nil
+	}
+	data, err := json.Marshal(f.times)
+	if err != nil {
+		return since, err
+	}
+	return since, fsutil.WriteFile(f.path, data, 0o600)
+}
+
+func sameTimes(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id, t := range a {
+		if other, ok := b[id]; !ok || !other.Equal(t) {
+			return false
+		}
+	}
+	return true
+}
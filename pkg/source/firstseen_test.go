@@ -0,0 +1,68 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/vrutkovs/todohub/pkg/issue"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FirstSeen", func() {
+	var (
+		path string
+		now  time.Time
+	)
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "state.json")
+		now = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	})
+
+	It("keeps times of items staying in the list", func() {
+		f, err := NewFirstSeen("")
+		Expect(err).NotTo(HaveOccurred())
+		fix := testIssue{title: "Fix", url: "https://example.com/1"}
+		Expect(f.Update("github", "To review", []issue.Issue{fix}, now)).To(Equal([]time.Time{now}))
+
+		later := now.Add(time.Hour)
+		Expect(f.Update("github", "To review", []issue.Issue{testIssue{title: "Bump"}, fix}, later)).To(Equal([]time.Time{later, now}))
+		// Lists of other sources are separate
+		Expect(f.Update("gitea", "To review", []issue.Issue{fix}, later)).To(Equal([]time.Time{later}))
+
+		// Dropped items are forgotten
+		Expect(f.Update("github", "To review", nil, later)).To(BeEmpty())
+		Expect(f.Update("github", "To review", []issue.Issue{fix}, later)).To(Equal([]time.Time{later}))
+	})
+
+	It("identifies items by key", func() {
+		f, err := NewFirstSeen("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Update("feed", "Status", []issue.Issue{keyedIssue{testIssue{title: "Old"}, "1"}}, now)).To(HaveLen(1))
+		since, err := f.Update("feed", "Status", []issue.Issue{keyedIssue{testIssue{title: "Renamed"}, "1"}}, now.Add(time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(since).To(Equal([]time.Time{now}))
+	})
+
+	It("persists times", func() {
+		f, err := NewFirstSeen(path)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.Update("github", "To review", []issue.Issue{testIssue{title: "Fix"}}, now)
+		Expect(err).NotTo(HaveOccurred())
+
+		f, err = NewFirstSeen(path)
+		Expect(err).NotTo(HaveOccurred())
+		since, err := f.Update("github", "To review", []issue.Issue{testIssue{title: "Fix"}}, now.Add(time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(since[0]).To(BeTemporally("==", now))
+	})
+
+	It("rejects broken state file", func() {
+		Expect(os.WriteFile(path, []byte("{"), 0o600)).To(Succeed())
+		_, err := NewFirstSeen(path)
+		Expect(err).To(MatchError(ContainSubstring("first seen: " + path)))
+	})
+})
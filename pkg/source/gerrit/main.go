@@ -0,0 +1,132 @@
+package gerrit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/backoff"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/metrics"
+	"github.com/vrutkovs/todohub/pkg/source"
+	"github.com/vrutkovs/todohub/pkg/storage"
+)
+
+// PageSize is a number of changes requested per page.
+const PageSize = 100
+
+// xssiPrefix is prepended by Gerrit to JSON responses.
+var xssiPrefix = []byte(")]}'")
+
+// Client holds information about gerrit client.
+type Client struct {
+	http     *http.Client
+	endpoint *url.URL
+	lists    *source.Lists[string]
+	settings *Settings
+	logger   *logrus.Logger
+}
+
+// New returns gerrit client.
+func New(s *Settings, storageClient storage.Client, logger *logrus.Logger) (*Client, error) {
+	endpoint, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		http:     backoff.NewClient(backoff.DefaultPolicy()),
+		endpoint: endpoint,
+		settings: s,
+		logger:   logger,
+	}
+	c.lists = source.NewLists("gerrit", s.SearchList, c.queryChanges, source.ToItem, storageClient, logger)
+	return c, nil
+}
+
+// Sync runs change queries and applies changes in storage.
+func (c *Client) Sync(description string) error {
+	return c.lists.Sync(description)
+}
+
+// changeInfo is a part of Gerrit ChangeInfo used by todohub.
+type changeInfo struct {
+	Number      int    `json:"_number"` //nolint:tagliatelle
+	Subject     string `json:"subject"`
+	Project     string `json:"project"`
+	MoreChanges bool   `json:"_more_changes"` //nolint:tagliatelle
+}
+
+// queryChanges fetches all pages of change query results.
+func (c *Client) queryChanges(query string) ([]issue.Issue, error) {
+	logger := c.logger.WithFields(logrus.Fields{"source": "gerrit", "query": query})
+	results := make([]issue.Issue, 0)
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("n", strconv.Itoa(PageSize))
+	for start := 0; ; start += PageSize {
+		params.Set("S", strconv.Itoa(start))
+		var changes []changeInfo
+		if err := c.get("changes/", params, &changes); err != nil {
+			metrics.APIError("gerrit")
+			logger.WithError(err).Error("query failed")
+			return nil, err
+		}
+		for _, change := range changes {
+			results = append(results, source.NewItem(change.Subject, c.changeURL(change), change.Project))
+		}
+		if len(changes) == 0 || !changes[len(changes)-1].MoreChanges {
+			break
+		}
+	}
+	logger.WithField("count", len(results)).Info("results fetched")
+	return results, nil
+}
+
+// changeURL builds web UI link to the change.
+func (c *Client) changeURL(change changeInfo) string {
+	return c.endpoint.JoinPath("c", change.Project, "+", strconv.Itoa(change.Number)).String()
+}
+
+// get sends GET request and decodes XSSI-prefixed JSON response.
+// Authenticated requests use "/a/" endpoint prefix.
+func (c *Client) get(path string, params url.Values, result interface{}) error {
+	u := c.endpoint.JoinPath(path)
+	if c.settings.Username != "" {
+		u = c.endpoint.JoinPath("a", path)
+	}
+	u.RawQuery = params.Encode()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.settings.Username != "" {
+		req.SetBasicAuth(c.settings.Username, c.settings.Password)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("gerrit: %s %s: %s: %s", req.Method, u.Path, resp.Status, bytes.TrimSpace(body))
+	}
+	return decodeJSON(body, result)
+}
+
+// decodeJSON strips XSSI protection prefix and decodes JSON.
+func decodeJSON(body []byte, result interface{}) error {
+	body = bytes.TrimPrefix(body, xssiPrefix)
+	return json.Unmarshal(body, result)
+}
@@ -0,0 +1,84 @@
+package gerrit
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/source"
+	"github.com/vrutkovs/todohub/pkg/storage/storagetest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestGerrit(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Gerrit")
+}
+
+var _ = DescribeTable("decodeJSON",
+	func(body string, expected []changeInfo) {
+		var changes []changeInfo
+		Expect(decodeJSON([]byte(body), &changes)).To(Succeed())
+		Expect(changes).To(Equal(expected))
+	},
+	Entry("XSSI prefix", ")]}'\n[{\"_number\":1,\"subject\":\"Fix\",\"project\":\"nova\"}]", []changeInfo{{Number: 1, Subject: "Fix", Project: "nova"}}),
+	Entry("No prefix", `[{"_number":2,"subject":"Add","project":"neutron"}]`, []changeInfo{{Number: 2, Subject: "Add", Project: "neutron"}}),
+	Entry("Empty", ")]}'\n[]", []changeInfo{}),
+)
+
+var _ = Describe("Sync", func() {
+	It("creates cards linking to changes", func() {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/a/changes/"))
+			user, password, ok := r.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(user).To(Equal("user"))
+			Expect(password).To(Equal("httppassword"))
+			Expect(r.URL.Query().Get("q")).To(Equal("reviewer:self status:open -owner:self"))
+
+			fmt.Fprint(w, ")]}'\n")
+			switch r.URL.Query().Get("S") {
+			case "0":
+				fmt.Fprint(w, `[{"_number":1,"subject":"Fix crash","project":"openstack/nova","_more_changes":true}]`)
+			default:
+				fmt.Fprint(w, `[{"_number":2,"subject":"Add feature","project":"openstack/neutron"}]`)
+			}
+		}))
+		defer srv.Close()
+
+		storageClient := storagetest.New(nil)
+		c, err := New(&Settings{
+			Endpoint:   srv.URL,
+			Username:   "user",
+			Password:   "httppassword",
+			SearchList: map[string]string{"To review": "reviewer:self status:open -owner:self"},
+		}, storageClient, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Sync("test")).To(Succeed())
+
+		Expect(storageClient.Lists["To review"]).To(ConsistOf(
+			issue.Issue(source.NewItem("Fix crash", srv.URL+"/c/openstack/nova/+/1", "openstack/nova")),
+			issue.Issue(source.NewItem("Add feature", srv.URL+"/c/openstack/neutron/+/2", "openstack/neutron")),
+		))
+	})
+
+	It("uses anonymous endpoint without credentials", func() {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/changes/"))
+			fmt.Fprint(w, ")]}'\n[]")
+		}))
+		defer srv.Close()
+
+		c, err := New(&Settings{
+			Endpoint:   srv.URL,
+			SearchList: map[string]string{"Open": "status:open"},
+		}, storagetest.New(nil), logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Sync("test")).To(Succeed())
+	})
+})
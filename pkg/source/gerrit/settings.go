@@ -0,0 +1,19 @@
+package gerrit
+
+// Settings stores info about Gerrit connection.
+type Settings struct {
+	Endpoint string `yaml:"endpoint"`
+	// Username and HTTP password generated in Gerrit user settings.
+	Username   string            `yaml:"username,omitempty"`
+	Password   string            `yaml:"password,omitempty"`
+	SearchList map[string]string `yaml:"lists"`
+}
+
+// Implement source.Settings.
+func (s Settings) ID() string {
+	return "gerrit"
+}
+
+func (s Settings) Searches() map[string]string {
+	return s.SearchList
+}
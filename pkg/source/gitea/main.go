@@ -0,0 +1,143 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/backoff"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/metrics"
+	"github.com/vrutkovs/todohub/pkg/source"
+	"github.com/vrutkovs/todohub/pkg/storage"
+)
+
+// PageSize is a number of issues requested per page.
+const PageSize = 50
+
+// Client holds information about gitea client.
+type Client struct {
+	http     *http.Client
+	endpoint *url.URL
+	lists    *source.Lists[Filter]
+	settings *Settings
+	logger   *logrus.Logger
+}
+
+// New returns gitea client.
+func New(s *Settings, storageClient storage.Client, logger *logrus.Logger) (*Client, error) {
+	endpoint, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		http:     backoff.NewClient(backoff.DefaultPolicy()),
+		endpoint: endpoint,
+		settings: s,
+		logger:   logger,
+	}
+	c.lists = source.NewLists("gitea", s.SearchList, c.searchIssues, source.ToItem, storageClient, logger)
+	return c, nil
+}
+
+// Sync runs issue searches and applies changes in storage.
+func (c *Client) Sync(description string) error {
+	return c.lists.Sync(description)
+}
+
+// apiIssue is a part of Gitea issue used by todohub.
+type apiIssue struct {
+	Title      string `json:"title"`
+	HTMLURL    string `json:"html_url"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// query builds issue search parameters from the filter.
+func (f Filter) query() url.Values {
+	params := url.Values{}
+	state := f.State
+	if state == "" {
+		state = "open"
+	}
+	params.Set("state", state)
+	if f.Type != "" {
+		params.Set("type", f.Type)
+	}
+	if f.ReviewRequested {
+		params.Set("review_requested", "true")
+	}
+	if f.Assigned {
+		params.Set("assigned", "true")
+	}
+	if f.Created {
+		params.Set("created", "true")
+	}
+	if f.Mentioned {
+		params.Set("mentioned", "true")
+	}
+	if len(f.Labels) > 0 {
+		params.Set("labels", strings.Join(f.Labels, ","))
+	}
+	if f.Owner != "" {
+		params.Set("owner", f.Owner)
+	}
+	if f.Query != "" {
+		params.Set("q", f.Query)
+	}
+	return params
+}
+
+// searchIssues fetches all pages of issue search results.
+func (c *Client) searchIssues(filter Filter) ([]issue.Issue, error) {
+	logger := c.logger.WithFields(logrus.Fields{"source": "gitea", "filter": filter})
+	results := make([]issue.Issue, 0)
+	params := filter.query()
+	params.Set("limit", strconv.Itoa(PageSize))
+	for page := 1; ; page++ {
+		params.Set("page", strconv.Itoa(page))
+		var issues []apiIssue
+		if err := c.get("api/v1/repos/issues/search", params, &issues); err != nil {
+			metrics.APIError("gitea")
+			logger.WithError(err).Error("search failed")
+			return nil, err
+		}
+		for _, i := range issues {
+			results = append(results, source.NewItem(i.Title, i.HTMLURL, i.Repository.FullName))
+		}
+		if len(issues) < PageSize {
+			break
+		}
+	}
+	logger.WithField("count", len(results)).Info("results fetched")
+	return results, nil
+}
+
+// get sends authenticated GET request and decodes JSON response.
+func (c *Client) get(path string, params url.Values, result interface{}) error {
+	u := c.endpoint.JoinPath(path)
+	u.RawQuery = params.Encode()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.settings.Token != "" {
+		req.Header.Set("Authorization", "token "+c.settings.Token)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("gitea: %s %s: %s", req.Method, u.Path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
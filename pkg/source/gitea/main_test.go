@@ -0,0 +1,115 @@
+package gitea
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/source"
+	"github.com/vrutkovs/todohub/pkg/storage/storagetest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestGitea(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Gitea")
+}
+
+// fakeGitea serves issue search with two pages of results.
+func fakeGitea() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Expect(r.URL.Path).To(Equal("/api/v1/repos/issues/search"))
+		Expect(r.Header.Get("Authorization")).To(Equal("token s3cr3t"))
+		q := r.URL.Query()
+		Expect(q.Get("state")).To(Equal("open"))
+		Expect(q.Get("type")).To(Equal("pulls"))
+		Expect(q.Get("review_requested")).To(Equal("true"))
+		Expect(q.Get("labels")).To(Equal("bug,infra"))
+
+		page, _ := strconv.Atoi(q.Get("page"))
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		issues := []map[string]interface{}{}
+		if page == 1 {
+			for i := range limit {
+				issues = append(issues, map[string]interface{}{
+					"title":      "PR " + strconv.Itoa(i),
+					"html_url":   "https://forgejo.example.com/infra/tools/pulls/" + strconv.Itoa(i),
+					"repository": map[string]string{"full_name": "infra/tools"},
+				})
+			}
+		} else if page == 2 {
+			issues = append(issues, map[string]interface{}{
+				"title":      "Last PR",
+				"html_url":   "https://forgejo.example.com/infra/other/pulls/1",
+				"repository": map[string]string{"full_name": "infra/other"},
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		Expect(json.NewEncoder(w).Encode(issues)).To(Succeed())
+	}))
+}
+
+var _ = DescribeTable("Filter.query",
+	func(f Filter, expected string) {
+		Expect(f.query().Encode()).To(Equal(expected))
+	},
+	Entry("Empty", Filter{}, "state=open"),
+	Entry("Review requested PRs", Filter{Type: "pulls", ReviewRequested: true}, "review_requested=true&state=open&type=pulls"),
+	Entry("All filters", Filter{
+		Type:      "issues",
+		State:     "all",
+		Assigned:  true,
+		Created:   true,
+		Mentioned: true,
+		Labels:    []string{"a", "b"},
+		Owner:     "infra",
+		Query:     "flaky",
+	}, "assigned=true&created=true&labels=a%2Cb&mentioned=true&owner=infra&q=flaky&state=all&type=issues"),
+)
+
+var _ = Describe("Sync", func() {
+	It("creates and removes cards", func() {
+		srv := fakeGitea()
+		defer srv.Close()
+
+		stale := source.NewItem("Stale PR", "https://forgejo.example.com/infra/tools/pulls/999", "infra/tools")
+		kept := source.NewItem("PR 1", "https://forgejo.example.com/infra/tools/pulls/1", "infra/tools")
+		storageClient := storagetest.New(map[string][]issue.Issue{
+			"To review": {stale, kept},
+		})
+		c, err := New(&Settings{
+			Endpoint: srv.URL,
+			Token:    "s3cr3t",
+			SearchList: map[string]Filter{
+				"To review": {Type: "pulls", ReviewRequested: true, Labels: []string{"bug", "infra"}},
+			},
+		}, storageClient, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.Sync("test")).To(Succeed())
+		cards := storageClient.Lists["To review"]
+		Expect(cards).To(HaveLen(PageSize + 1))
+		Expect(cards).NotTo(ContainElement(stale))
+		Expect(cards).To(ContainElement(source.NewItem("Last PR", "https://forgejo.example.com/infra/other/pulls/1", "infra/other")))
+	})
+
+	It("returns API errors", func() {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer srv.Close()
+
+		c, err := New(&Settings{
+			Endpoint:   srv.URL,
+			SearchList: map[string]Filter{"Assigned": {Assigned: true}},
+		}, storagetest.New(nil), logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Sync("test")).To(MatchError(ContainSubstring("401 Unauthorized")))
+	})
+})
@@ -0,0 +1,27 @@
+package gitea
+
+// Settings stores info about Gitea/Forgejo connection.
+type Settings struct {
+	Endpoint   string            `yaml:"endpoint"`
+	Token      string            `yaml:"token"`
+	SearchList map[string]Filter `yaml:"lists"`
+}
+
+// Filter holds issue search filters for a list.
+type Filter struct {
+	// Type is either "issues" or "pulls", both are included if empty.
+	Type            string   `yaml:"type,omitempty"`
+	State           string   `yaml:"state,omitempty"`
+	ReviewRequested bool     `yaml:"review_requested,omitempty"`
+	Assigned        bool     `yaml:"assigned,omitempty"`
+	Created         bool     `yaml:"created,omitempty"`
+	Mentioned       bool     `yaml:"mentioned,omitempty"`
+	Labels          []string `yaml:"labels,omitempty"`
+	Owner           string   `yaml:"owner,omitempty"`
+	Query           string   `yaml:"query,omitempty"`
+}
+
+// Implement source.Settings.
+func (s Settings) ID() string {
+	return "gitea"
+}
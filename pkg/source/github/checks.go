@@ -0,0 +1,170 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	api "github.com/google/go-github/v28/github"
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/metrics"
+)
+
+// failedCheck is a status context or check run which did not pass.
+type failedCheck struct {
+	name string
+	url  string
+}
+
+// failingStatusStates are commit status states treated as failures.
+var failingStatusStates = map[string]bool{
+	"failure": true,
+	"error":   true,
+}
+
+// failingConclusions are check run conclusions treated as failures.
+var failingConclusions = map[string]bool{
+	"failure":         true,
+	"timed_out":       true,
+	"action_required": true,
+}
+
+// pullNumber extracts PR number from its web URL, e.g. https://github.com/o/r/pull/1.
+func pullNumber(htmlURL string) (int, bool) {
+	parts := strings.Split(strings.Trim(htmlURL, "/"), "/")
+	if len(parts) < 2 || parts[len(parts)-2] != "pull" {
+		return 0, false
+	}
+	number, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return 0, false
+	}
+	return number, true
+}
+
+// checksDescription formats failed checks for a card description.
+func checksDescription(checks []failedCheck) string {
+	var b strings.Builder
+	b.WriteString("Failing checks:")
+	for _, check := range checks {
+		b.WriteString("\n- " + check.name)
+		if check.url != "" {
+			b.WriteString(" (" + check.url + ")")
+		}
+	}
+	return b.String()
+}
+
+// withFailingChecks keeps PRs which have failing checks on their head commit
+// and adds failed check names to their description.
+func (c *Client) withFailingChecks(prs []Issue) ([]Issue, error) {
+	results := make([]Issue, 0, len(prs))
+	for _, pr := range prs {
+		number, ok := pullNumber(pr.url)
+		if !ok {
+			continue
+		}
+		checks, err := c.failedChecks(pr.repo, number)
+		if err != nil {
+			return nil, err
+		}
+		if len(checks) == 0 {
+			continue
+		}
+		pr.description = checksDescription(checks)
+		results = append(results, pr)
+	}
+	return results, nil
+}
+
+// failedChecks returns failed statuses and check runs for PR head commit.
+func (c *Client) failedChecks(repo string, number int) ([]failedCheck, error) {
+	ctx := context.Background()
+	logger := c.logger.WithFields(logrus.Fields{"source": "github", "repo": repo, "pr": number})
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid repo %q", repo)
+	}
+
+	pr, _, err := c.api.PullRequests.Get(ctx, owner, name, number)
+	if err != nil {
+		metrics.APIError("github")
+		return nil, err
+	}
+	sha := pr.GetHead().GetSHA()
+
+	failed := make([]failedCheck, 0)
+	statusOpts := &api.ListOptions{PerPage: 100}
+	for {
+		status, resp, err := c.api.Repositories.GetCombinedStatus(ctx, owner, name, sha, statusOpts)
+		if err != nil {
+			metrics.APIError("github")
+			return nil, err
+		}
+		for _, s := range status.Statuses {
+			if failingStatusStates[s.GetState()] {
+				failed = append(failed, failedCheck{name: s.GetContext(), url: s.GetTargetURL()})
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		statusOpts.Page = resp.NextPage
+	}
+
+	runOpts := &api.ListCheckRunsOptions{ListOptions: api.ListOptions{PerPage: 100}}
+	for {
+		runs, resp, err := c.api.Checks.ListCheckRunsForRef(ctx, owner, name, sha, runOpts)
+		if err != nil {
+			metrics.APIError("github")
+			return nil, err
+		}
+		for _, run := range runs.CheckRuns {
+			if failingConclusions[run.GetConclusion()] {
+				failed = append(failed, failedCheck{name: run.GetName(), url: run.GetHTMLURL()})
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		runOpts.Page = resp.NextPage
+	}
+
+	if !c.settings.RequiredChecksOnly || len(failed) == 0 {
+		return failed, nil
+	}
+	required, err := c.requiredChecks(owner, name, pr.GetBase().GetRef())
+	if err != nil {
+		logger.WithError(err).Warn("failed to fetch required checks, using all checks")
+		return failed, nil
+	}
+	results := make([]failedCheck, 0, len(failed))
+	for _, check := range failed {
+		if required[check.name] {
+			results = append(results, check)
+		}
+	}
+	return results, nil
+}
+
+// requiredChecks returns check names required by branch protection.
+// Unprotected branches have no required checks.
+func (c *Client) requiredChecks(owner, name, branch string) (map[string]bool, error) {
+	checks, _, err := c.api.Repositories.GetRequiredStatusChecks(context.Background(), owner, name, branch)
+	var errResp *api.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response.StatusCode == http.StatusNotFound {
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		metrics.APIError("github")
+		return nil, err
+	}
+	required := make(map[string]bool, len(checks.Contexts))
+	for _, check := range checks.Contexts {
+		required[check] = true
+	}
+	return required, nil
+}
@@ -6,12 +6,16 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/avast/retry-go"
 	api "github.com/google/go-github/v28/github"
 	"github.com/sirupsen/logrus"
 	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/metrics"
+	"github.com/vrutkovs/todohub/pkg/source"
 	"github.com/vrutkovs/todohub/pkg/storage"
+	"github.com/vrutkovs/todohub/pkg/webhook"
 	"golang.org/x/oauth2"
 )
 
@@ -25,6 +29,10 @@ type Client struct {
 	settings      *Settings
 	issueList     IssueList
 	logger        *logrus.Logger
+	syncMu        sync.Mutex
+	debouncer     *webhook.Debouncer
+	// notificationCards holds notification threads with cards per list.
+	notificationCards map[string]map[string]Issue
 }
 
 // New returns github client.
@@ -34,19 +42,26 @@ func New(s *Settings, storageClient storage.Client, logger *logrus.Logger) *Clie
 		&oauth2.Token{AccessToken: s.Token},
 	)
 	tc := oauth2.NewClient(ctx, ts)
-	return &Client{
+	c := &Client{
 		api:           api.NewClient(tc),
 		storageClient: &storageClient,
 		settings:      s,
 		logger:        logger,
+
+		notificationCards: make(map[string]map[string]Issue),
+	}
+	if s.Webhook != nil {
+		c.debouncer = webhook.NewDebouncer(webhook.Delay(s.Webhook.DebounceSeconds), c.webhookSync)
 	}
+	return c
 }
 
 // Issue implements source.Issue.
 type Issue struct {
-	title string
-	url   string
-	repo  string
+	title       string
+	url         string
+	repo        string
+	description string
 }
 
 func (i Issue) Title() string {
@@ -61,6 +76,11 @@ func (i Issue) Repo() string {
 	return i.repo
 }
 
+// Description lists failing checks for PRs in failing checks lists.
+func (i Issue) Description() string {
+	return i.description
+}
+
 // IssueList implements source.IssueList.
 type IssueList struct {
 	issues map[string][]Issue
@@ -73,21 +93,22 @@ func (i IssueList) Name(name string) []Issue {
 	return nil
 }
 
-func (c Client) Issues() IssueList {
+func (c *Client) Issues() IssueList {
 	return c.issueList
 }
 
 // WorkerData holds info about worker payload.
 type WorkerData struct {
-	project string
-	query   string
-	storage storage.Client
+	project       string
+	query         string
+	storage       storage.Client
+	failingChecks bool
 }
 
 // githubWorker runs queries in github.
-func (c *Client) githubWorker(wData WorkerData, wg *sync.WaitGroup) {
+func (c *Client) githubWorker(wData WorkerData) error {
 	logger := c.logger.WithFields(logrus.Fields{"source": "github", "project": wData.project})
-	defer wg.Done()
+	defer metrics.ObserveSync("github", wData.project, time.Now())
 
 	// Run the query
 	query := wData.query
@@ -96,97 +117,101 @@ func (c *Client) githubWorker(wData WorkerData, wg *sync.WaitGroup) {
 	}
 	searchResults, err := c.getIssueInfoForSearchQuery(query)
 	if err != nil {
-		c.logger.Fatal(err)
+		return err
 	}
 	logger.Info("fetched search results")
-	// Build a new list of issues from search results
-	required := issue.List{
-		Issues: make([]issue.Issue, len(searchResults)),
+	if wData.failingChecks {
+		searchResults, err = c.withFailingChecks(searchResults)
+		if err != nil {
+			return err
+		}
+		logger.WithField("count", len(searchResults)).Info("found PRs with failing checks")
 	}
+	metrics.ItemsFetched.WithLabelValues("github", wData.project).Set(float64(len(searchResults)))
+	// Build a new list of issues from search results
+	required := make([]issue.Issue, len(searchResults))
 	for i, issue := range searchResults {
-		required.Issues[i] = Issue{
-			title: issue.title,
-			url:   issue.url,
-			repo:  issue.repo,
+		required[i] = Issue{
+			title:       issue.title,
+			url:         issue.url,
+			repo:        issue.repo,
+			description: issue.description,
 		}
 	}
 
-	// Create a list if its missing
-	logger.Info("fetching existing cards")
-	err = wData.storage.CreateProject(wData.project)
-	if err != nil {
-		c.logger.Fatal(err)
-	}
+	return source.SyncList("github", wData.project, wData.storage, required, toIssue, logger)
+}
 
-	// Fetch existing cards and mark all cards for removal
-	existingIssues, err := wData.storage.GetIssues(wData.project)
-	if err != nil {
-		c.logger.Fatal(err)
-	}
-	existing := issue.List{
-		Issues: make([]issue.Issue, len(existingIssues)),
+// toIssue drops internal storage values to make intersection work.
+func toIssue(i issue.Issue) issue.Issue {
+	return Issue{
+		title: i.Title(),
+		url:   i.URL(),
+		repo:  i.Repo(),
 	}
+}
 
-	// Drop internal values to make intersection work
-	for i, issue := range existingIssues {
-		existing.Issues[i] = Issue{
-			title: issue.Title(),
-			url:   issue.URL(),
-			repo:  issue.Repo(),
-		}
+// Sync runs search queries and applies changes in storage.
+func (c *Client) Sync(description string) error {
+	err := errors.Join(
+		c.syncLists(description, c.settings.SearchList, false),
+		c.syncLists(description, c.settings.FailingChecks, true),
+		c.syncNotifications(description),
+	)
+	if err != nil {
+		return err
 	}
+	metrics.SyncSucceeded("github")
+	return nil
+}
 
-	titleOnlyComparison := (*c.storageClient).CompareByTitleOnly()
-
-	// Create an intersection from these two lists
-	hashExisting := existing.MakeHashList(titleOnlyComparison)
-	hashRequired := required.MakeHashList(titleOnlyComparison)
-	// Remove all cards in existing which are not in intersection
-	logger.Info("removing old cards")
-	for _, el := range issue.OuterSection(hashExisting, hashRequired).Issues {
-		err := wData.storage.Delete(wData.project, el)
-		if err != nil {
-			c.logger.Fatal(err)
+// SyncLists runs search queries for selected lists only.
+func (c *Client) SyncLists(description string, lists []string) error {
+	searches := make(map[string]string, len(lists))
+	checks := make(map[string]string, len(lists))
+	for _, name := range lists {
+		if query, ok := c.settings.SearchList[name]; ok {
+			searches[name] = query
 		}
-		logger.WithFields(logrus.Fields{"item": el.Title()}).Info("removing old card")
-	}
-
-	// Add all cards from required which are not in intersection
-	logger.Info("adding new cards")
-	for _, i := range issue.OuterSection(hashRequired, hashExisting).Issues {
-		err := wData.storage.Create(wData.project, i)
-		if err != nil {
-			c.logger.Fatal(err)
+		if query, ok := c.settings.FailingChecks[name]; ok {
+			checks[name] = query
 		}
-		logger.WithFields(logrus.Fields{"item": i.Title()}).Info("created card")
-	}
-	if err := wData.storage.Sync(query); err != nil {
-		c.logger.Fatal(err)
 	}
-	logger.Info("done")
+	return errors.Join(
+		c.syncLists(description, searches, false),
+		c.syncLists(description, checks, true),
+	)
 }
 
-// Sync runs search queries and applies changes in storage.
-func (c *Client) Sync(description string) error {
-	var wg sync.WaitGroup
+// syncLists runs search queries and applies changes in storage.
+// PRs without failing checks are skipped if failingChecks is set.
+func (c *Client) syncLists(description string, searches map[string]string, failingChecks bool) error {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+
 	storageClient := *c.storageClient
+	var errs []error
 
 	logger := c.logger.WithFields(logrus.Fields{"source": "github", "description": description})
 	logger.Info("syncing")
-	for project, query := range c.settings.SearchList {
+	for project, query := range searches {
 		logger.WithField("project", project).Info("started")
 		workerData := WorkerData{
-			project: project,
-			query:   query,
-			storage: storageClient,
+			project:       project,
+			query:         query,
+			storage:       storageClient,
+			failingChecks: failingChecks,
+		}
+		if err := c.githubWorker(workerData); err != nil {
+			logger.WithField("project", project).WithError(err).Error("failed")
+			errs = append(errs, err)
+			continue
 		}
-		wg.Add(1)
-
-		c.githubWorker(workerData, &wg)
 		logger.WithField("project", project).Info("done")
-
 	}
-	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
 	logger.Info("sync completed")
 	return nil
 }
@@ -198,8 +223,12 @@ func (c *Client) getIssueInfoForSearchQuery(searchQuery string) ([]Issue, error)
 	results := make([]Issue, 0)
 	err := retry.Do(
 		func() error {
-			result, _, err := c.api.Search.Issues(ctx, searchQuery, opts)
+			result, resp, err := c.api.Search.Issues(ctx, searchQuery, opts)
+			if resp != nil {
+				metrics.GithubRateLimitRemaining.Set(float64(resp.Rate.Remaining))
+			}
 			if err != nil {
+				metrics.APIError("github")
 				c.logger.WithFields(logrus.Fields{"source": "github", "query": searchQuery}).WithError(err)
 				return err
 			}
@@ -1,7 +1,24 @@
 package github
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
+
+	api "github.com/google/go-github/v28/github"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/metrics"
+	"github.com/vrutkovs/todohub/pkg/storage/storagetest"
+	"github.com/vrutkovs/todohub/pkg/webhook"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -20,3 +37,281 @@ var _ = DescribeTable("repoSlug",
 	Entry("Empty", "", ""),
 	Entry("Invalid URL", "https://github.com", ""),
 )
+
+var _ = DescribeTable("queryMatchesRepo",
+	func(query string, expected bool) {
+		Expect(queryMatchesRepo(query, "vrutkovs/todohub")).To(Equal(expected))
+	},
+	Entry("Unscoped", "is:open review-requested:username", true),
+	Entry("Same repo", "is:open repo:vrutkovs/todohub", true),
+	Entry("Other repo", "is:open repo:vrutkovs/other", false),
+	Entry("One of repos", "repo:vrutkovs/other repo:Vrutkovs/Todohub", true),
+	Entry("Same org", "is:open org:vrutkovs", true),
+	Entry("Other user", "is:open user:someone", false),
+)
+
+var _ = Describe("WebhookHandler", func() {
+	const secret = "s3cr3t"
+	payload := `{"action":"opened","repository":{"full_name":"vrutkovs/todohub"}}`
+
+	newRequest := func(event, signature string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(payload))
+		req.Header.Set("X-GitHub-Event", event)
+		if signature != "" {
+			req.Header.Set("X-Hub-Signature-256", signature)
+		}
+		return req
+	}
+	sign := func(body string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	var (
+		client  *Client
+		handler http.Handler
+		synced  chan []string
+	)
+
+	BeforeEach(func() {
+		synced = make(chan []string, 1)
+		client = &Client{
+			settings: &Settings{
+				SearchList: map[string]string{
+					"To review": "review-requested:username",
+					"Other":     "repo:vrutkovs/other",
+				},
+				Webhook: &webhook.Settings{Secret: secret},
+			},
+			logger: logrus.New(),
+		}
+		client.debouncer = webhook.NewDebouncer(time.Millisecond, func(lists []string) {
+			synced <- lists
+		})
+		var err error
+		handler, err = client.WebhookHandler()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("requires a secret", func() {
+		_, err := (&Client{settings: &Settings{}}).WebhookHandler()
+		Expect(err).To(MatchError(errNoWebhookSecret))
+	})
+
+	It("rejects invalid signatures", func() {
+		for _, signature := range []string{"", "sha256=deadbeef"} {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, newRequest("pull_request", signature))
+			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+		}
+		Consistently(synced, 50*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("ignores other events", func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newRequest("ping", sign(payload)))
+		Expect(rec.Code).To(Equal(http.StatusAccepted))
+		Consistently(synced, 50*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("resyncs affected lists", func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newRequest("pull_request", sign(payload)))
+		Expect(rec.Code).To(Equal(http.StatusAccepted))
+		Eventually(synced).Should(Receive(Equal([]string{"To review"})))
+	})
+})
+
+func notification(id, reason, repo, subjectType, subjectURL, title string) *api.Notification {
+	return &api.Notification{
+		ID:     api.String(id),
+		Reason: api.String(reason),
+		Repository: &api.Repository{
+			FullName: api.String(repo),
+			HTMLURL:  api.String("https://github.com/" + repo),
+		},
+		Subject: &api.NotificationSubject{
+			Title: api.String(title),
+			URL:   api.String(subjectURL),
+			Type:  api.String(subjectType),
+		},
+	}
+}
+
+var _ = DescribeTable("notificationURL",
+	func(n *api.Notification, expected string) {
+		Expect(notificationURL(n)).To(Equal(expected))
+	},
+	Entry("Pull request", notification("1", "review_requested", "vrutkovs/todohub", "PullRequest", "https://api.github.com/repos/vrutkovs/todohub/pulls/42", ""), "https://github.com/vrutkovs/todohub/pull/42"),
+	Entry("Issue", notification("1", "mention", "vrutkovs/todohub", "Issue", "https://api.github.com/repos/vrutkovs/todohub/issues/7", ""), "https://github.com/vrutkovs/todohub/issues/7"),
+	Entry("Commit", notification("1", "mention", "vrutkovs/todohub", "Commit", "https://api.github.com/repos/vrutkovs/todohub/commits/abc", ""), "https://github.com/vrutkovs/todohub/commit/abc"),
+	Entry("Check suite", notification("1", "ci_activity", "vrutkovs/todohub", "CheckSuite", "", ""), "https://github.com/vrutkovs/todohub/actions"),
+	Entry("Release", notification("1", "subscribed", "vrutkovs/todohub", "Release", "https://api.github.com/repos/vrutkovs/todohub/releases/1", ""), "https://github.com/vrutkovs/todohub"),
+)
+
+var _ = Describe("Notifications", func() {
+	var (
+		srv           *httptest.Server
+		markedRead    []string
+		storageClient *storagetest.Storage
+		client        *Client
+	)
+
+	BeforeEach(func() {
+		markedRead = nil
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/notifications":
+				Expect(json.NewEncoder(w).Encode([]*api.Notification{
+					notification("1", "review_requested", "vrutkovs/todohub", "PullRequest", "https://api.github.com/repos/vrutkovs/todohub/pulls/1", "Review me"),
+					notification("2", "mention", "vrutkovs/todohub", "Issue", "https://api.github.com/repos/vrutkovs/todohub/issues/2", "Mentioned"),
+					notification("3", "review_requested", "other/repo", "PullRequest", "https://api.github.com/repos/other/repo/pulls/3", "Other repo"),
+				})).To(Succeed())
+			case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/notifications/threads/"):
+				markedRead = append(markedRead, strings.TrimPrefix(r.URL.Path, "/notifications/threads/"))
+				w.WriteHeader(http.StatusResetContent)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		storageClient = storagetest.New(nil)
+		client = New(&Settings{
+			Notifications: map[string]NotificationFilter{
+				"Review requests": {Reasons: []string{"review_requested"}, Repos: []string{"vrutkovs/todohub"}},
+			},
+			MarkReadOnComplete: true,
+		}, storageClient, logrus.New())
+		baseURL, err := url.Parse(srv.URL + "/")
+		Expect(err).NotTo(HaveOccurred())
+		client.api.BaseURL = baseURL
+	})
+
+	AfterEach(func() {
+		srv.Close()
+	})
+
+	It("creates cards for matching notifications", func() {
+		Expect(client.Sync("test")).To(Succeed())
+		Expect(storageClient.Lists["Review requests"]).To(ConsistOf(issue.Issue(Issue{
+			title: "Review me",
+			url:   "https://github.com/vrutkovs/todohub/pull/1",
+			repo:  "vrutkovs/todohub",
+		})))
+		Expect(markedRead).To(BeEmpty())
+	})
+
+	It("marks notification read when card is completed", func() {
+		Expect(client.Sync("test")).To(Succeed())
+		storageClient.Lists["Review requests"] = []issue.Issue{}
+
+		Expect(client.Sync("test")).To(Succeed())
+		Expect(markedRead).To(Equal([]string{"1"}))
+		Expect(storageClient.Lists["Review requests"]).To(BeEmpty())
+	})
+})
+
+var _ = DescribeTable("pullNumber",
+	func(htmlURL string, expected int, expectedOK bool) {
+		number, ok := pullNumber(htmlURL)
+		Expect(ok).To(Equal(expectedOK))
+		Expect(number).To(Equal(expected))
+	},
+	Entry("Pull request", "https://github.com/vrutkovs/todohub/pull/42", 42, true),
+	Entry("Issue", "https://github.com/vrutkovs/todohub/issues/42", 0, false),
+	Entry("Invalid number", "https://github.com/vrutkovs/todohub/pull/abc", 0, false),
+)
+
+var _ = Describe("Failing checks", func() {
+	var (
+		srv           *httptest.Server
+		protected     bool
+		storageClient *storagetest.Storage
+		settings      *Settings
+	)
+
+	BeforeEach(func() {
+		protected = true
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/search/issues":
+				Expect(json.NewEncoder(w).Encode(api.IssuesSearchResult{Issues: []api.Issue{
+					{Title: api.String("Broken"), HTMLURL: api.String("https://github.com/vrutkovs/todohub/pull/1"), RepositoryURL: api.String("https://api.github.com/repos/vrutkovs/todohub")},
+					{Title: api.String("Green"), HTMLURL: api.String("https://github.com/vrutkovs/todohub/pull/2"), RepositoryURL: api.String("https://api.github.com/repos/vrutkovs/todohub")},
+				}})).To(Succeed())
+			case "/repos/vrutkovs/todohub/pulls/1":
+				Expect(json.NewEncoder(w).Encode(api.PullRequest{Head: &api.PullRequestBranch{SHA: api.String("broken")}, Base: &api.PullRequestBranch{Ref: api.String("master")}})).To(Succeed())
+			case "/repos/vrutkovs/todohub/pulls/2":
+				Expect(json.NewEncoder(w).Encode(api.PullRequest{Head: &api.PullRequestBranch{SHA: api.String("green")}, Base: &api.PullRequestBranch{Ref: api.String("master")}})).To(Succeed())
+			case "/repos/vrutkovs/todohub/commits/broken/status":
+				Expect(json.NewEncoder(w).Encode(api.CombinedStatus{Statuses: []api.RepoStatus{
+					{Context: api.String("ci/prow/lint"), State: api.String("failure"), TargetURL: api.String("https://prow/lint")},
+					{Context: api.String("ci/prow/unit"), State: api.String("success")},
+				}})).To(Succeed())
+			case "/repos/vrutkovs/todohub/commits/broken/check-runs":
+				Expect(json.NewEncoder(w).Encode(api.ListCheckRunsResults{CheckRuns: []*api.CheckRun{
+					{Name: api.String("e2e"), Conclusion: api.String("timed_out"), HTMLURL: api.String("https://github.com/runs/1")},
+				}})).To(Succeed())
+			case "/repos/vrutkovs/todohub/commits/green/status":
+				Expect(json.NewEncoder(w).Encode(api.CombinedStatus{})).To(Succeed())
+			case "/repos/vrutkovs/todohub/commits/green/check-runs":
+				Expect(json.NewEncoder(w).Encode(api.ListCheckRunsResults{CheckRuns: []*api.CheckRun{
+					{Name: api.String("e2e"), Conclusion: api.String("success")},
+				}})).To(Succeed())
+			case "/repos/vrutkovs/todohub/branches/master/protection/required_status_checks":
+				if !protected {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				Expect(json.NewEncoder(w).Encode(api.RequiredStatusChecks{Contexts: []string{"e2e"}})).To(Succeed())
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		storageClient = storagetest.New(nil)
+		settings = &Settings{
+			FailingChecks: map[string]string{"Failing PRs": "is:pr author:vrutkovs"},
+		}
+	})
+
+	AfterEach(func() {
+		srv.Close()
+	})
+
+	runSync := func() {
+		client := New(settings, storageClient, logrus.New())
+		baseURL, err := url.Parse(srv.URL + "/")
+		Expect(err).NotTo(HaveOccurred())
+		client.api.BaseURL = baseURL
+		Expect(client.Sync("test")).To(Succeed())
+	}
+
+	It("keeps PRs with failing checks", func() {
+		runSync()
+		Expect(testutil.ToFloat64(metrics.ItemsFetched.WithLabelValues("github", "Failing PRs"))).To(Equal(1.0))
+		Expect(storageClient.Lists["Failing PRs"]).To(ConsistOf(issue.Issue(Issue{
+			title:       "Broken",
+			url:         "https://github.com/vrutkovs/todohub/pull/1",
+			repo:        "vrutkovs/todohub",
+			description: "Failing checks:\n- ci/prow/lint (https://prow/lint)\n- e2e (https://github.com/runs/1)",
+		})))
+	})
+
+	It("keeps only required checks", func() {
+		settings.RequiredChecksOnly = true
+		runSync()
+		Expect(storageClient.Lists["Failing PRs"]).To(ConsistOf(issue.Issue(Issue{
+			title:       "Broken",
+			url:         "https://github.com/vrutkovs/todohub/pull/1",
+			repo:        "vrutkovs/todohub",
+			description: "Failing checks:\n- e2e (https://github.com/runs/1)",
+		})))
+	})
+
+	It("skips unprotected branches in required checks mode", func() {
+		settings.RequiredChecksOnly = true
+		protected = false
+		runSync()
+		Expect(storageClient.Lists["Failing PRs"]).To(BeEmpty())
+	})
+})
@@ -0,0 +1,193 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	api "github.com/google/go-github/v28/github"
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/metrics"
+	"github.com/vrutkovs/todohub/pkg/source"
+	"github.com/vrutkovs/todohub/pkg/storage"
+)
+
+// NotificationFilter selects unread notifications for a list.
+type NotificationFilter struct {
+	// Reasons, e.g. mention, review_requested, assign or ci_activity. All reasons match if empty.
+	Reasons []string `yaml:"reasons,omitempty"`
+	// Repos in owner/name form. All repos match if empty.
+	Repos []string `yaml:"repos,omitempty"`
+}
+
+// matches returns true if notification passes the filter.
+func (f NotificationFilter) matches(n *api.Notification) bool {
+	if len(f.Reasons) > 0 && !containsFold(f.Reasons, n.GetReason()) {
+		return false
+	}
+	if len(f.Repos) > 0 && !containsFold(f.Repos, n.GetRepository().GetFullName()) {
+		return false
+	}
+	return true
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// notificationURL builds a web link to notification subject.
+func notificationURL(n *api.Notification) string {
+	repoURL := n.GetRepository().GetHTMLURL()
+	subject := n.GetSubject()
+	if subject.GetType() == "CheckSuite" {
+		return repoURL + "/actions"
+	}
+	u, err := url.Parse(subject.GetURL())
+	if err != nil || subject.GetURL() == "" {
+		return repoURL
+	}
+	// API URLs look like /repos/owner/name/pulls/1
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 {
+		return repoURL
+	}
+	kind, id := parts[len(parts)-2], parts[len(parts)-1]
+	switch kind {
+	case "pulls":
+		return repoURL + "/pull/" + id
+	case "issues":
+		return repoURL + "/issues/" + id
+	case "commits":
+		return repoURL + "/commit/" + id
+	}
+	return repoURL
+}
+
+// fetchNotifications returns all unread notifications.
+func (c *Client) fetchNotifications() ([]*api.Notification, error) {
+	ctx := context.Background()
+	opts := &api.NotificationListOptions{
+		ListOptions: api.ListOptions{PerPage: 50},
+	}
+	results := make([]*api.Notification, 0)
+	for {
+		notifications, resp, err := c.api.Activity.ListNotifications(ctx, opts)
+		if resp != nil {
+			metrics.GithubRateLimitRemaining.Set(float64(resp.Rate.Remaining))
+		}
+		if err != nil {
+			metrics.APIError("github")
+			return nil, err
+		}
+		results = append(results, notifications...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return results, nil
+}
+
+// syncNotifications applies notification lists to storage.
+func (c *Client) syncNotifications(description string) error {
+	if len(c.settings.Notifications) == 0 {
+		return nil
+	}
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+
+	logger := c.logger.WithFields(logrus.Fields{"source": "github", "description": description})
+	logger.Info("syncing notifications")
+	notifications, err := c.fetchNotifications()
+	if err != nil {
+		return err
+	}
+	logger.WithField("count", len(notifications)).Info("fetched notifications")
+
+	var errs []error
+	for project, filter := range c.settings.Notifications {
+		if err := c.notificationWorker(project, filter, notifications, *c.storageClient); err != nil {
+			logger.WithField("project", project).WithError(err).Error("failed")
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// notificationWorker syncs notifications matching the filter into the list.
+func (c *Client) notificationWorker(project string, filter NotificationFilter, notifications []*api.Notification, storageClient storage.Client) error {
+	logger := c.logger.WithFields(logrus.Fields{"source": "github", "project": project})
+	defer metrics.ObserveSync("github", project, time.Now())
+
+	threads := make(map[string]Issue)
+	for _, n := range notifications {
+		if !filter.matches(n) {
+			continue
+		}
+		threads[n.GetID()] = Issue{
+			title: n.GetSubject().GetTitle(),
+			url:   notificationURL(n),
+			repo:  n.GetRepository().GetFullName(),
+		}
+	}
+
+	if c.settings.MarkReadOnComplete {
+		if err := c.markCompletedThreadsRead(project, threads, storageClient); err != nil {
+			return err
+		}
+	}
+
+	metrics.ItemsFetched.WithLabelValues("github", project).Set(float64(len(threads)))
+	required := make([]issue.Issue, 0, len(threads))
+	for _, i := range threads {
+		required = append(required, i)
+	}
+	if err := source.SyncList("github", project, storageClient, required, toIssue, logger); err != nil {
+		return err
+	}
+
+	if c.settings.MarkReadOnComplete {
+		c.notificationCards[project] = threads
+	}
+	return nil
+}
+
+// markCompletedThreadsRead marks threads read if their cards were created earlier
+// and no longer exist in storage, and drops them from the list.
+// Cards created before restart are not tracked.
+func (c *Client) markCompletedThreadsRead(project string, threads map[string]Issue, storageClient storage.Client) error {
+	created := c.notificationCards[project]
+	if len(created) == 0 {
+		return nil
+	}
+	existingIssues, err := storageClient.GetIssues(project)
+	if err != nil {
+		metrics.APIError(source.StorageBackend)
+		return err
+	}
+	existing := issue.List{Issues: existingIssues}
+
+	for id, i := range threads {
+		if _, wasCreated := created[id]; !wasCreated {
+			continue
+		}
+		if _, found := existing.Get(i.title); found {
+			continue
+		}
+		if _, err := c.api.Activity.MarkThreadRead(context.Background(), id); err != nil {
+			metrics.APIError("github")
+			return err
+		}
+		c.logger.WithFields(logrus.Fields{"source": "github", "project": project, "item": i.title}).Info("marked notification read")
+		delete(threads, id)
+	}
+	return nil
+}
@@ -1,11 +1,22 @@
 package github
 
+import "github.com/vrutkovs/todohub/pkg/webhook"
+
 // Settings stores info about github connection.
 type Settings struct {
 	Token        string            `yaml:"token"`
 	BoardID      string            `yaml:"project,omitempty"`
 	SearchPrefix string            `yaml:"search_prefix,omitempty"`
 	SearchList   map[string]string `yaml:"lists"`
+	// FailingChecks maps list names to PR searches, keeping only PRs with failing checks.
+	FailingChecks map[string]string `yaml:"failing_checks,omitempty"`
+	// RequiredChecksOnly ignores failed checks which are not required by branch protection.
+	RequiredChecksOnly bool              `yaml:"required_checks_only,omitempty"`
+	Webhook            *webhook.Settings `yaml:"webhook,omitempty"`
+	// Notifications maps list names to unread notification filters.
+	Notifications map[string]NotificationFilter `yaml:"notifications,omitempty"`
+	// MarkReadOnComplete marks notification read once its card is completed in storage.
+	MarkReadOnComplete bool `yaml:"mark_read_on_complete,omitempty"`
 }
 
 // Implement source.Settings.
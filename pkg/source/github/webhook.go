@@ -0,0 +1,148 @@
+package github
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	api "github.com/google/go-github/v28/github"
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/webhook"
+)
+
+var (
+	errNoWebhookSecret = errors.New("github webhook secret is not set")
+	errIgnoredEvent    = errors.New("event ignored")
+)
+
+// webhookEvents lists GitHub events which trigger a resync.
+var webhookEvents = map[string]bool{
+	"pull_request":        true,
+	"pull_request_review": true,
+	"issues":              true,
+}
+
+// WebhookHandler returns HTTP handler for GitHub webhooks.
+func (c *Client) WebhookHandler() (http.Handler, error) {
+	if c.settings.Webhook == nil || c.settings.Webhook.Secret == "" {
+		return nil, errNoWebhookSecret
+	}
+	secret := []byte(c.settings.Webhook.Secret)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		eventType := api.WebHookType(r)
+		logger := c.logger.WithFields(logrus.Fields{"source": "github", "event": eventType, "delivery": api.DeliveryID(r)})
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, webhook.MaxPayloadBytes))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := validateSignature(r, payload, secret); err != nil {
+			logger.WithError(err).Warn("invalid webhook signature")
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		repo, err := eventRepo(eventType, payload)
+		switch {
+		case errors.Is(err, errIgnoredEvent):
+			w.WriteHeader(http.StatusAccepted)
+			return
+		case err != nil:
+			logger.WithError(err).Warn("failed to parse webhook")
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		lists := c.affectedLists(repo)
+		logger.WithFields(logrus.Fields{"repo": repo, "lists": lists}).Info("scheduling resync")
+		c.debouncer.Add(lists...)
+		w.WriteHeader(http.StatusAccepted)
+	}), nil
+}
+
+// validateSignature checks payload HMAC, preferring SHA-256 signature.
+func validateSignature(r *http.Request, payload, secret []byte) error {
+	signature := r.Header.Get("X-Hub-Signature-256")
+	if signature == "" {
+		signature = r.Header.Get("X-Hub-Signature")
+	}
+	return api.ValidateSignature(signature, payload, secret)
+}
+
+// eventRepo returns full name of the repo the event belongs to.
+func eventRepo(eventType string, payload []byte) (string, error) {
+	if !webhookEvents[eventType] {
+		return "", errIgnoredEvent
+	}
+	event, err := api.ParseWebHook(eventType, payload)
+	if err != nil {
+		return "", err
+	}
+	switch e := event.(type) {
+	case *api.PullRequestEvent:
+		return e.GetRepo().GetFullName(), nil
+	case *api.PullRequestReviewEvent:
+		return e.GetRepo().GetFullName(), nil
+	case *api.IssuesEvent:
+		return e.GetRepo().GetFullName(), nil
+	}
+	return "", errIgnoredEvent
+}
+
+// affectedLists returns lists which may contain items from the repo.
+func (c *Client) affectedLists(repo string) []string {
+	lists := make([]string, 0)
+	for _, searches := range []map[string]string{c.settings.SearchList, c.settings.FailingChecks} {
+		for name, query := range searches {
+			if queryMatchesRepo(c.settings.SearchPrefix+" "+query, repo) {
+				lists = append(lists, name)
+			}
+		}
+	}
+	return lists
+}
+
+// queryMatchesRepo returns false if search query is scoped to other repos or owners.
+func queryMatchesRepo(query, repo string) bool {
+	owner, _, _ := strings.Cut(repo, "/")
+	var repos, owners []string
+	for _, term := range strings.Fields(query) {
+		key, value, found := strings.Cut(term, ":")
+		if !found {
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "repo":
+			repos = append(repos, value)
+		case "org", "user":
+			owners = append(owners, value)
+		}
+	}
+	if len(repos) == 0 && len(owners) == 0 {
+		return true
+	}
+	for _, r := range repos {
+		if strings.EqualFold(r, repo) {
+			return true
+		}
+	}
+	for _, o := range owners {
+		if strings.EqualFold(o, owner) {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookSync resyncs lists affected by webhook events.
+func (c *Client) webhookSync(lists []string) {
+	if err := c.SyncLists("webhook", lists); err != nil {
+		c.logger.WithField("source", "github").WithError(err).Error("webhook sync failed")
+	}
+}
@@ -0,0 +1,66 @@
+package jira
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+	jira "github.com/andygrunwald/go-jira/v2/onpremise"
+)
+
+// newSearchAPI returns Jira Cloud or Server/Data Center client depending on settings.
+func newSearchAPI(s *Settings) (searchAPI, error) {
+	httpClient, err := s.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	if s.Cloud {
+		client, err := cloud.NewClient(s.Endpoint, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		return cloudAPI{client: client}, nil
+	}
+	client, err := jira.NewClient(s.Endpoint, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return onpremiseAPI{client: client}, nil
+}
+
+// searchResult holds issue fields used by todohub.
+type searchResult struct {
+	key     string
+	summary string
+	project string
+	due     time.Time
+}
+
+// searchAPI abstracts issue search differences between Jira Server/Data Center and Cloud.
+type searchAPI interface {
+	// SearchPages calls f for every issue matching the JQL query.
+	SearchPages(ctx context.Context, jql string, f func(searchResult) error) error
+	// BaseURL returns Jira site URL.
+	BaseURL() *url.URL
+}
+
+// onpremiseAPI runs searches against Jira Server/Data Center.
+type onpremiseAPI struct {
+	client *jira.Client
+}
+
+func (a onpremiseAPI) SearchPages(ctx context.Context, jql string, f func(searchResult) error) error {
+	return a.client.Issue.SearchPages(ctx, jql, nil, func(i jira.Issue) error {
+		return f(searchResult{
+			key:     i.Key,
+			summary: i.Fields.Summary,
+			project: i.Fields.Project.Key,
+			due:     time.Time(i.Fields.Duedate),
+		})
+	})
+}
+
+func (a onpremiseAPI) BaseURL() *url.URL {
+	return a.client.BaseURL
+}
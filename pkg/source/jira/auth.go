@@ -0,0 +1,151 @@
+package jira
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira/v2/onpremise"
+)
+
+// ErrNoCloudEmail is returned when Jira Cloud credentials have no account email.
+var ErrNoCloudEmail = errors.New("jira cloud requires account email")
+
+const (
+	// AuthBearer sends token as bearer token (personal access token).
+	AuthBearer = "bearer"
+	// AuthBasic uses username and password.
+	AuthBasic = "basic"
+	// AuthCookie creates a session using username and password.
+	AuthCookie = "cookie"
+)
+
+// Connection holds Jira site and authentication settings.
+// It is shared by jira source and storage.
+type Connection struct {
+	Endpoint string `yaml:"endpoint"`
+	Token    string `yaml:"token"`
+	// Cloud enables Atlassian Cloud API, authenticated with Email and API Token.
+	Cloud bool          `yaml:"cloud,omitempty"`
+	Email string        `yaml:"email,omitempty"`
+	Auth  *AuthSettings `yaml:"auth,omitempty"`
+	TLS   *TLSSettings  `yaml:"tls,omitempty"`
+}
+
+// AuthSettings holds Jira authentication settings.
+type AuthSettings struct {
+	// Type is one of "bearer", "basic" or "cookie".
+	// Defaults to "bearer" for Server/Data Center and "basic" for Cloud.
+	Type     string `yaml:"type,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+}
+
+// TLSSettings holds client certificate and custom CA settings.
+type TLSSettings struct {
+	CertFile string `yaml:"cert_file,omitempty"`
+	KeyFile  string `yaml:"key_file,omitempty"`
+	CAFile   string `yaml:"ca_file,omitempty"`
+}
+
+// authType returns configured auth type or default for the deployment.
+func (s *Connection) authType() string {
+	if s.Auth != nil && s.Auth.Type != "" {
+		return strings.ToLower(s.Auth.Type)
+	}
+	if s.Cloud {
+		return AuthBasic
+	}
+	return AuthBearer
+}
+
+// credentials returns username and password for basic and cookie auth.
+// Cloud falls back to account email and API token.
+func (s *Connection) credentials() (string, string) {
+	if s.Auth != nil && s.Auth.Username != "" {
+		return s.Auth.Username, s.Auth.Password
+	}
+	return s.Email, s.Token
+}
+
+// HTTPClient returns HTTP client with configured TLS and authentication.
+func (s *Connection) HTTPClient() (*http.Client, error) {
+	if s.Cloud {
+		if username, _ := s.credentials(); username == "" {
+			return nil, ErrNoCloudEmail
+		}
+	}
+	transport, err := s.TLS.transport()
+	if err != nil {
+		return nil, err
+	}
+
+	var rt http.RoundTripper
+	switch s.authType() {
+	case AuthBearer:
+		if s.Token == "" {
+			// mTLS-only setups don't need a token
+			return &http.Client{Transport: transport}, nil
+		}
+		rt = &jira.BearerAuthTransport{
+			Token:     s.Token,
+			Transport: transport,
+		}
+	case AuthBasic:
+		username, password := s.credentials()
+		rt = &jira.BasicAuthTransport{
+			Username:  username,
+			Password:  password,
+			Transport: transport,
+		}
+	case AuthCookie:
+		username, password := s.credentials()
+		rt = &jira.CookieAuthTransport{
+			Username:  username,
+			Password:  password,
+			AuthURL:   strings.TrimSuffix(s.Endpoint, "/") + "/rest/auth/1/session",
+			Transport: transport,
+		}
+	default:
+		return nil, fmt.Errorf("unknown jira auth type %q", s.Auth.Type)
+	}
+	return &http.Client{Transport: rt}, nil
+}
+
+// transport returns HTTP transport with client certificate and CA bundle.
+func (t *TLSSettings) transport() (http.RoundTripper, error) {
+	if t == nil {
+		return http.DefaultTransport, nil
+	}
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", t.CAFile)
+		}
+		config.RootCAs = pool
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config
+	return transport, nil
+}
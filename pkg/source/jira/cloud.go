@@ -0,0 +1,86 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// CloudPageSize is a number of issues requested per search page in Jira Cloud.
+const CloudPageSize = 100
+
+// cloudAPI runs searches against Jira Cloud.
+type cloudAPI struct {
+	client *cloud.Client
+}
+
+// dueDateFormat is a format of Jira due date field.
+const dueDateFormat = "2006-01-02"
+
+// cloudSearchPage is a page of /rest/api/3/search/jql results.
+type cloudSearchPage struct {
+	Issues []struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary string `json:"summary"`
+			Project struct {
+				Key string `json:"key"`
+			} `json:"project"`
+			DueDate string `json:"duedate"`
+		} `json:"fields"`
+	} `json:"issues"`
+	NextPageToken string `json:"nextPageToken"` //nolint:tagliatelle
+	IsLast        bool   `json:"isLast"`        //nolint:tagliatelle
+}
+
+// SearchPages follows nextPageToken pagination of the enhanced JQL search.
+func (a cloudAPI) SearchPages(ctx context.Context, jql string, f func(searchResult) error) error {
+	nextPageToken := ""
+	for {
+		params := url.Values{}
+		params.Set("jql", jql)
+		params.Set("fields", "summary,project,duedate")
+		params.Set("maxResults", strconv.Itoa(CloudPageSize))
+		if nextPageToken != "" {
+			params.Set("nextPageToken", nextPageToken)
+		}
+		req, err := a.client.NewRequest(ctx, http.MethodGet, "rest/api/3/search/jql?"+params.Encode(), nil)
+		if err != nil {
+			return err
+		}
+		page := new(cloudSearchPage)
+		resp, err := a.client.Do(req, page)
+		if err != nil {
+			return cloud.NewJiraError(resp, err)
+		}
+		for _, i := range page.Issues {
+			result := searchResult{
+				key:     i.Key,
+				summary: i.Fields.Summary,
+				project: i.Fields.Project.Key,
+			}
+			if i.Fields.DueDate != "" {
+				due, err := time.Parse(dueDateFormat, i.Fields.DueDate)
+				if err != nil {
+					return err
+				}
+				result.due = due
+			}
+			if err := f(result); err != nil {
+				return err
+			}
+		}
+		if page.IsLast || page.NextPageToken == "" {
+			return nil
+		}
+		nextPageToken = page.NextPageToken
+	}
+}
+
+func (a cloudAPI) BaseURL() *url.URL {
+	return a.client.BaseURL
+}
@@ -2,22 +2,28 @@ package jira
 
 import (
 	"context"
+	"errors"
 	"sync"
+	"time"
 
-	jira "github.com/andygrunwald/go-jira/v2/onpremise"
 	"github.com/avast/retry-go"
 	"github.com/sirupsen/logrus"
 	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/metrics"
+	"github.com/vrutkovs/todohub/pkg/source"
 	"github.com/vrutkovs/todohub/pkg/storage"
+	"github.com/vrutkovs/todohub/pkg/webhook"
 )
 
 // Client holds information about jira client.
 type Client struct {
-	api           *jira.Client
+	api           searchAPI
 	storageClient *storage.Client
 	settings      *Settings
 	issueList     IssueList
 	logger        *logrus.Logger
+	syncMu        sync.Mutex
+	debouncer     *webhook.Debouncer
 }
 
 // WorkerData holds info about worker payload.
@@ -32,6 +38,7 @@ type Issue struct {
 	title   string
 	url     string
 	project string
+	due     time.Time
 }
 
 func (i Issue) Title() string {
@@ -46,6 +53,11 @@ func (i Issue) Repo() string {
 	return i.project
 }
 
+// Due returns issue due date.
+func (i Issue) Due() time.Time {
+	return i.due
+}
+
 // IssueList implements source.IssueList.
 type IssueList struct {
 	issues map[string][]Issue
@@ -58,127 +70,110 @@ func (i IssueList) Name(name string) []Issue {
 	return nil
 }
 
-func (c Client) Issues() IssueList {
+func (c *Client) Issues() IssueList {
 	return c.issueList
 }
 
 // New returns jira client.
 func New(s *Settings, storageClient storage.Client, logger *logrus.Logger) (*Client, error) {
-	tp := jira.BearerAuthTransport{
-		Token: s.Token,
-	}
-	client, err := jira.NewClient(s.Endpoint, tp.Client())
+	client, err := newSearchAPI(s)
 	if err != nil {
 		return nil, err
 	}
-	return &Client{
+	c := &Client{
 		api:           client,
 		storageClient: &storageClient,
 		settings:      s,
 		logger:        logger,
-	}, nil
+	}
+	if s.Webhook != nil {
+		c.debouncer = webhook.NewDebouncer(webhook.Delay(s.Webhook.DebounceSeconds), c.webhookSync)
+	}
+	return c, nil
 }
 
 // Sync runs search queries and applies changes in storage.
 func (c *Client) Sync(description string) error {
-	var wg sync.WaitGroup
+	if err := c.syncLists(description, c.settings.SearchList); err != nil {
+		return err
+	}
+	metrics.SyncSucceeded("jira")
+	return nil
+}
+
+// SyncLists runs search queries for selected lists only.
+func (c *Client) SyncLists(description string, lists []string) error {
+	searches := make(map[string]string, len(lists))
+	for _, name := range lists {
+		if query, ok := c.settings.SearchList[name]; ok {
+			searches[name] = query
+		}
+	}
+	return c.syncLists(description, searches)
+}
+
+// syncLists runs search queries and applies changes in storage.
+func (c *Client) syncLists(description string, searches map[string]string) error {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+
 	storageClient := *c.storageClient
+	var errs []error
 
 	logger := c.logger.WithFields(logrus.Fields{"source": "jira", "desc": description})
 
 	logger.Info("syncing")
-	for project, query := range c.settings.SearchList {
+	for project, query := range searches {
 		workerData := WorkerData{
 			project: project,
 			query:   query,
 			storage: storageClient,
 		}
-		wg.Add(1)
-
-		c.jiraWorker(workerData, &wg)
+		if err := c.jiraWorker(workerData); err != nil {
+			logger.WithField("project", project).WithError(err).Error("failed")
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
 	}
-	wg.Wait()
 	logger.Info("syncing done")
 	return nil
 }
 
 // jiraWorker runs queries in jira.
-func (c *Client) jiraWorker(wData WorkerData, wg *sync.WaitGroup) {
+func (c *Client) jiraWorker(wData WorkerData) error {
 	logger := c.logger.WithFields(logrus.Fields{"source": "jira", "project": wData.project})
-	defer wg.Done()
+	defer metrics.ObserveSync("jira", wData.project, time.Now())
 
 	// Run the query
 	query := wData.query
 	searchResults, err := c.getIssueInfoForSearchQuery(query)
 	if err != nil {
-		logger.Fatal(err)
+		return err
 	}
 	logger.Info("fetched search results")
-	// log.Printf("github: fetched search results for project '%s'", wData.project)
+	metrics.ItemsFetched.WithLabelValues("jira", wData.project).Set(float64(len(searchResults)))
 	// Build a new list of issues from search results
-	required := issue.List{
-		Issues: make([]issue.Issue, len(searchResults)),
-	}
+	required := make([]issue.Issue, len(searchResults))
 	for i, issue := range searchResults {
-		required.Issues[i] = Issue{
+		required[i] = Issue{
 			title:   issue.title,
 			url:     issue.url,
 			project: issue.project,
+			due:     issue.due,
 		}
 	}
 
-	// Create a list if its missing
-	logger.Info("fetching existing cards")
-	err = wData.storage.CreateProject(wData.project)
-	if err != nil {
-		logger.Fatal(err)
-	}
-
-	// Fetch existing cards and mark all cards for removal
-	existingIssues, err := wData.storage.GetIssues(wData.project)
-	if err != nil {
-		logger.Fatal(err)
-	}
-	logger.WithField("count", len(existingIssues)).Info("fetched existing cards")
-	existing := issue.List{
-		Issues: make([]issue.Issue, len(existingIssues)),
-	}
-
-	// Drop internal values to make intersection work
-	for i, issue := range existingIssues {
-		existing.Issues[i] = Issue{
-			title:   issue.Title(),
-			url:     issue.URL(),
-			project: issue.Repo(),
-		}
-	}
-
-	titleOnlyComparison := (*c.storageClient).CompareByTitleOnly()
-
-	// Create an intersection from these two lists
-	hashExisting := existing.MakeHashList(titleOnlyComparison)
-	hashRequired := required.MakeHashList(titleOnlyComparison)
-	// Remove all cards in existing which are not in intersection
-	logger.Info("removing old cards")
-	for _, el := range issue.OuterSection(hashExisting, hashRequired).Issues {
-		err := wData.storage.Delete(wData.project, el)
-		if err != nil {
-			logger.Fatal(err)
-		}
-		logger.WithField("item", el.Title()).Info("removed")
-	}
+	return source.SyncList("jira", wData.project, wData.storage, required, toIssue, logger)
+}
 
-	// Add all cards from required which are not in intersection
-	logger.Info("adding new cards")
-	for _, i := range issue.OuterSection(hashRequired, hashExisting).Issues {
-		err := wData.storage.Create(wData.project, i)
-		if err != nil {
-			logger.Fatal(err)
-		}
-		logger.WithField("item", i.Title()).Info("created")
-	}
-	if err := wData.storage.Sync(query); err != nil {
-		logger.Fatal(err)
+// toIssue drops internal storage values to make intersection work.
+func toIssue(i issue.Issue) issue.Issue {
+	return Issue{
+		title:   i.Title(),
+		url:     i.URL(),
+		project: i.Repo(),
 	}
 }
 
@@ -188,19 +183,25 @@ func (c *Client) getIssueInfoForSearchQuery(searchQuery string) ([]Issue, error)
 	logger.Info("starting")
 
 	ctx := context.Background()
-	results := make([]Issue, 0)
+	var results []Issue
 	err := retry.Do(
 		func() error {
-			appendFunc := func(i jira.Issue) (err error) {
+			// pages fetched by failed attempt are fetched again
+			results = make([]Issue, 0)
+			appendFunc := func(i searchResult) (err error) {
 				result := Issue{
-					title:   i.Fields.Summary,
-					url:     c.buildJiraTicketUrl(i.Key),
-					project: i.Fields.Project.Key,
+					title:   i.summary,
+					url:     c.buildJiraTicketUrl(i.key),
+					project: i.project,
+					due:     i.due,
 				}
 				results = append(results, result)
 				return nil
 			}
-			err := c.api.Issue.SearchPages(ctx, searchQuery, nil, appendFunc)
+			err := c.api.SearchPages(ctx, searchQuery, appendFunc)
+			if err != nil {
+				metrics.APIError("jira")
+			}
 			logger.WithError(err).Info("done")
 			return err
 		},
@@ -210,5 +211,5 @@ func (c *Client) getIssueInfoForSearchQuery(searchQuery string) ([]Issue, error)
 }
 
 func (c *Client) buildJiraTicketUrl(key string) string {
-	return c.api.BaseURL.JoinPath("browse", key).String()
+	return c.api.BaseURL().JoinPath("browse", key).String()
 }
@@ -0,0 +1,205 @@
+package jira
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestJira(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Jira")
+}
+
+var _ = DescribeTable("queryMatchesProject",
+	func(query string, expected bool) {
+		Expect(queryMatchesProject(query, "10000", "OCPBUGS", "OpenShift Bugs")).To(Equal(expected))
+	},
+	Entry("Unscoped", "assignee = currentUser() AND resolution = Unresolved", true),
+	Entry("Same project", "project = OCPBUGS AND assignee = currentUser()", true),
+	Entry("Same project by name", `project = "OpenShift Bugs"`, true),
+	Entry("Other project", "project = OTHER AND assignee = currentUser()", false),
+	Entry("Project list", "project in (OTHER, ocpbugs)", true),
+	Entry("Other projects list", "project IN (OTHER, \"Another\")", false),
+	Entry("Negated project", "project != OCPBUGS", true),
+	Entry("With OR", "project = OTHER OR reporter = currentUser()", true),
+)
+
+var _ = DescribeTable("validateSecret",
+	func(target, signature string, valid bool) {
+		body := []byte(`{"webhookEvent":"jira:issue_updated"}`)
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(string(body)))
+		if signature != "" {
+			req.Header.Set("X-Hub-Signature", signature)
+		}
+		err := validateSecret(req, body, []byte("s3cr3t"))
+		if valid {
+			Expect(err).NotTo(HaveOccurred())
+		} else {
+			Expect(err).To(MatchError(errInvalidSecret))
+		}
+	},
+	Entry("Query secret", "/webhooks/jira?secret=s3cr3t", "", true),
+	Entry("Wrong query secret", "/webhooks/jira?secret=foo", "", false),
+	Entry("Missing secret", "/webhooks/jira", "", false),
+	Entry("Signature", "/webhooks/jira", sign(`{"webhookEvent":"jira:issue_updated"}`), true),
+	Entry("Wrong signature", "/webhooks/jira", "sha256=deadbeef", false),
+	Entry("Unsupported signature", "/webhooks/jira", "sha1=deadbeef", false),
+)
+
+func sign(body string) string {
+	mac := hmac.New(sha256.New, []byte("s3cr3t"))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+var _ = Describe("Cloud search", func() {
+	It("follows nextPageToken and builds browse URLs", func() {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/rest/api/3/search/jql"))
+			Expect(r.URL.Query().Get("jql")).To(Equal("assignee = currentUser()"))
+			user, token, ok := r.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(user).To(Equal("user@example.com"))
+			Expect(token).To(Equal("apitoken"))
+
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Query().Get("nextPageToken") {
+			case "":
+				fmt.Fprint(w, `{"issues":[{"key":"ABC-1","fields":{"summary":"First","project":{"key":"ABC"}}}],"nextPageToken":"page2","isLast":false}`)
+			case "page2":
+				fmt.Fprint(w, `{"issues":[{"key":"XYZ-2","fields":{"summary":"Second","project":{"key":"XYZ"},"duedate":"2024-05-01"}}],"isLast":true}`)
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}))
+		defer srv.Close()
+
+		api, err := newSearchAPI(&Settings{Connection: Connection{
+			Endpoint: srv.URL,
+			Token:    "apitoken",
+			Cloud:    true,
+			Email:    "user@example.com",
+		}})
+		Expect(err).NotTo(HaveOccurred())
+		c := &Client{api: api, logger: logrus.New()}
+		issues, err := c.getIssueInfoForSearchQuery("assignee = currentUser()")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(Equal([]Issue{
+			{title: "First", url: srv.URL + "/browse/ABC-1", project: "ABC"},
+			{title: "Second", url: srv.URL + "/browse/XYZ-2", project: "XYZ", due: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		}))
+	})
+
+	It("doesn't duplicate issues when retrying partial search", func() {
+		failed := false
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Query().Get("nextPageToken") {
+			case "":
+				fmt.Fprint(w, `{"issues":[{"key":"ABC-1","fields":{"summary":"First","project":{"key":"ABC"}}}],"nextPageToken":"page2","isLast":false}`)
+			case "page2":
+				if !failed {
+					failed = true
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+				fmt.Fprint(w, `{"issues":[{"key":"ABC-2","fields":{"summary":"Second","project":{"key":"ABC"}}}],"isLast":true}`)
+			}
+		}))
+		defer srv.Close()
+
+		api, err := newSearchAPI(&Settings{Connection: Connection{
+			Endpoint: srv.URL,
+			Token:    "apitoken",
+			Cloud:    true,
+			Email:    "user@example.com",
+		}})
+		Expect(err).NotTo(HaveOccurred())
+		c := &Client{api: api, logger: logrus.New()}
+		issues, err := c.getIssueInfoForSearchQuery("assignee = currentUser()")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(failed).To(BeTrue())
+		Expect(issues).To(Equal([]Issue{
+			{title: "First", url: srv.URL + "/browse/ABC-1", project: "ABC"},
+			{title: "Second", url: srv.URL + "/browse/ABC-2", project: "ABC"},
+		}))
+	})
+
+	It("requires account email", func() {
+		_, err := newSearchAPI(&Settings{Connection: Connection{Endpoint: "https://example.atlassian.net", Cloud: true}})
+		Expect(err).To(MatchError(ErrNoCloudEmail))
+	})
+})
+
+var _ = DescribeTable("HTTPClient auth",
+	func(s Connection, expected string) {
+		var header string
+		srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			header = r.Header.Get("Authorization")
+		}))
+		defer srv.Close()
+
+		client, err := s.HTTPClient()
+		Expect(err).NotTo(HaveOccurred())
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, http.NoBody)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(header).To(Equal(expected))
+	},
+	Entry("Bearer", Connection{Token: "pat"}, "Bearer pat"),
+	Entry("No token", Connection{}, ""),
+	Entry("Basic", Connection{Auth: &AuthSettings{Type: "basic", Username: "user", Password: "pass"}}, "Basic dXNlcjpwYXNz"),
+	Entry("Cloud", Connection{Cloud: true, Email: "user", Token: "pass"}, "Basic dXNlcjpwYXNz"),
+)
+
+var _ = Describe("HTTPClient TLS", func() {
+	It("rejects unknown auth type", func() {
+		_, err := (&Connection{Auth: &AuthSettings{Type: "kerberos"}}).HTTPClient()
+		Expect(err).To(MatchError(`unknown jira auth type "kerberos"`))
+	})
+
+	It("trusts custom CA bundle", func() {
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		caFile := filepath.Join(GinkgoT().TempDir(), "ca.pem")
+		Expect(os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: srv.Certificate().Raw,
+		}), 0o600)).To(Succeed())
+
+		client, err := (&Connection{TLS: &TLSSettings{CAFile: caFile}}).HTTPClient()
+		Expect(err).NotTo(HaveOccurred())
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, http.NoBody)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+	})
+
+	It("fails on missing client certificate", func() {
+		_, err := (&Connection{TLS: &TLSSettings{CertFile: "/no/such/cert", KeyFile: "/no/such/key"}}).HTTPClient()
+		Expect(err).To(MatchError(ContainSubstring("failed to load client certificate")))
+	})
+})
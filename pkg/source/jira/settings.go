@@ -1,9 +1,11 @@
 package jira
 
+import "github.com/vrutkovs/todohub/pkg/webhook"
+
 type Settings struct {
-	Endpoint   string            `yaml:"endpoint"`
-	Token      string            `yaml:"token"`
+	Connection `yaml:",inline"`
 	SearchList map[string]string `yaml:"lists"`
+	Webhook    *webhook.Settings `yaml:"webhook,omitempty"`
 }
 
 // Implement source.Settings.
@@ -0,0 +1,162 @@
+package jira
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/webhook"
+)
+
+var (
+	errNoWebhookSecret = errors.New("jira webhook secret is not set")
+	errInvalidSecret   = errors.New("invalid webhook secret")
+)
+
+// webhookEvents lists Jira events which trigger a resync.
+var webhookEvents = map[string]bool{
+	"jira:issue_created": true,
+	"jira:issue_updated": true,
+	"jira:issue_deleted": true,
+}
+
+var (
+	projectClauseRegex = regexp.MustCompile(`(?i)\bproject\s*(?:=|\bin\b)\s*(\([^)]*\)|"[^"]*"|'[^']*'|[^\s()]+)`)
+	orRegex            = regexp.MustCompile(`(?i)\bor\b`)
+)
+
+// webhookPayload holds parts of Jira webhook payload used to find affected lists.
+type webhookPayload struct {
+	WebhookEvent string `json:"webhookEvent"` //nolint:tagliatelle
+	Issue        struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Project struct {
+				ID   string `json:"id"`
+				Key  string `json:"key"`
+				Name string `json:"name"`
+			} `json:"project"`
+		} `json:"fields"`
+	} `json:"issue"`
+}
+
+// WebhookHandler returns HTTP handler for Jira Server/Data Center webhooks.
+// The secret is verified either from HMAC signature header or from "secret" query parameter.
+func (c *Client) WebhookHandler() (http.Handler, error) {
+	if c.settings.Webhook == nil || c.settings.Webhook.Secret == "" {
+		return nil, errNoWebhookSecret
+	}
+	secret := []byte(c.settings.Webhook.Secret)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := c.logger.WithField("source", "jira")
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, webhook.MaxPayloadBytes))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := validateSecret(r, body, secret); err != nil {
+			logger.WithError(err).Warn("invalid webhook secret")
+			http.Error(w, "invalid secret", http.StatusUnauthorized)
+			return
+		}
+
+		var payload webhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			logger.WithError(err).Warn("failed to parse webhook")
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !webhookEvents[payload.WebhookEvent] {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		project := payload.Issue.Fields.Project
+		lists := c.affectedLists(project.ID, project.Key, project.Name)
+		logger.WithFields(logrus.Fields{
+			"event": payload.WebhookEvent,
+			"issue": payload.Issue.Key,
+			"lists": lists,
+		}).Info("scheduling resync")
+		c.debouncer.Add(lists...)
+		w.WriteHeader(http.StatusAccepted)
+	}), nil
+}
+
+// validateSecret checks HMAC signature if present, otherwise compares "secret" query parameter.
+func validateSecret(r *http.Request, body, secret []byte) error {
+	if signature := r.Header.Get("X-Hub-Signature"); signature != "" {
+		algo, sum, found := strings.Cut(signature, "=")
+		if !found || algo != "sha256" {
+			return errInvalidSecret
+		}
+		expected, err := hex.DecodeString(sum)
+		if err != nil {
+			return errInvalidSecret
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(body)
+		if !hmac.Equal(mac.Sum(nil), expected) {
+			return errInvalidSecret
+		}
+		return nil
+	}
+	if subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("secret")), secret) != 1 {
+		return errInvalidSecret
+	}
+	return nil
+}
+
+// affectedLists returns lists whose JQL may match an issue from the project.
+func (c *Client) affectedLists(projectIDs ...string) []string {
+	lists := make([]string, 0)
+	for name, query := range c.settings.SearchList {
+		if queryMatchesProject(query, projectIDs...) {
+			lists = append(lists, name)
+		}
+	}
+	return lists
+}
+
+// queryMatchesProject returns false if JQL is restricted to other projects.
+// Queries with OR are never considered restricted.
+func queryMatchesProject(query string, projectIDs ...string) bool {
+	if orRegex.MatchString(query) {
+		return true
+	}
+	clauses := projectClauseRegex.FindAllStringSubmatch(query, -1)
+	if len(clauses) == 0 {
+		return true
+	}
+	for _, clause := range clauses {
+		for _, value := range strings.Split(strings.Trim(clause[1], "()"), ",") {
+			value = strings.Trim(strings.TrimSpace(value), `"'`)
+			for _, id := range projectIDs {
+				if id != "" && strings.EqualFold(value, id) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// webhookSync resyncs lists affected by webhook events.
+func (c *Client) webhookSync(lists []string) {
+	if err := c.SyncLists("webhook", lists); err != nil {
+		c.logger.WithField("source", "jira").WithError(err).Error("webhook sync failed")
+	}
+}
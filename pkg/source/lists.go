@@ -0,0 +1,121 @@
+package source
+
+import (
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/metrics"
+	"github.com/vrutkovs/todohub/pkg/storage"
+)
+
+// FetchFunc returns items matching the list query.
+type FetchFunc[Q any] func(query Q) ([]issue.Issue, error)
+
+// Lists syncs lists of a source which only has to fetch items for list queries.
+type Lists[Q any] struct {
+	sourceID string
+	queries  map[string]Q
+	fetch    FetchFunc[Q]
+	convert  ConvertFunc
+	storage  storage.Client
+	logger   *logrus.Logger
+}
+
+// NewLists returns lists of the source.
+// Convert drops storage values from existing items, e.g. ToItem or ToKeyedItem.
+func NewLists[Q any](sourceID string, queries map[string]Q, fetch FetchFunc[Q], convert ConvertFunc, storageClient storage.Client, logger *logrus.Logger) *Lists[Q] {
+	return &Lists[Q]{
+		sourceID: sourceID,
+		queries:  queries,
+		fetch:    fetch,
+		convert:  convert,
+		storage:  storageClient,
+		logger:   logger,
+	}
+}
+
+// Sync fetches all lists and applies changes in storage.
+func (l *Lists[Q]) Sync(description string) error {
+	var errs []error
+
+	logger := l.logger.WithFields(logrus.Fields{"source": l.sourceID, "description": description})
+	logger.Info("syncing")
+	for project, query := range l.queries {
+		if err := l.syncList(project, query); err != nil {
+			logger.WithField("project", project).WithError(err).Error("failed")
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	metrics.SyncSucceeded(l.sourceID)
+	logger.Info("sync completed")
+	return nil
+}
+
+// syncList fetches a single list.
+func (l *Lists[Q]) syncList(project string, query Q) error {
+	logger := l.logger.WithFields(logrus.Fields{"source": l.sourceID, "project": project})
+	defer metrics.ObserveSync(l.sourceID, project, time.Now())
+
+	required, err := l.fetch(query)
+	if err != nil {
+		return err
+	}
+	logger.WithField("count", len(required)).Info("fetched search results")
+	metrics.ItemsFetched.WithLabelValues(l.sourceID, project).Set(float64(len(required)))
+
+	return SyncList(l.sourceID, project, l.storage, required, l.convert, logger)
+}
+
+// Item is a source item with title, link and repo only.
+type Item struct {
+	title string
+	url   string
+	repo  string
+}
+
+// NewItem returns item.
+func NewItem(title, url, repo string) Item {
+	return Item{title: title, url: url, repo: repo}
+}
+
+func (i Item) Title() string {
+	return i.title
+}
+
+func (i Item) URL() string {
+	return i.url
+}
+
+func (i Item) Repo() string {
+	return i.repo
+}
+
+// KeyedItem is a source item with a stable identifier.
+type KeyedItem struct {
+	Item
+	key string
+}
+
+// NewKeyedItem returns keyed item.
+func NewKeyedItem(key, title, url, repo string) KeyedItem {
+	return KeyedItem{Item: NewItem(title, url, repo), key: key}
+}
+
+func (i KeyedItem) Key() string {
+	return i.key
+}
+
+// ToItem drops internal storage values to make intersection work.
+func ToItem(i issue.Issue) issue.Issue {
+	return NewItem(i.Title(), i.URL(), i.Repo())
+}
+
+// ToKeyedItem drops internal storage values keeping the key.
+func ToKeyedItem(i issue.Issue) issue.Issue {
+	return NewKeyedItem(issue.Key(i), i.Title(), i.URL(), i.Repo())
+}
@@ -0,0 +1,64 @@
+package source
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/metrics"
+	"github.com/vrutkovs/todohub/pkg/storage/storagetest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lists", func() {
+	var (
+		storageClient *storagetest.Storage
+		results       map[string][]issue.Issue
+		errFetch      = errors.New("fetch failed")
+	)
+
+	fetch := func(query string) ([]issue.Issue, error) {
+		items, ok := results[query]
+		if !ok {
+			return nil, errFetch
+		}
+		return items, nil
+	}
+
+	BeforeEach(func() {
+		storageClient = storagetest.New(map[string][]issue.Issue{
+			"Bugs": {NewKeyedItem("1", "Old title", "https://example.com/1", "app")},
+		})
+		results = map[string][]issue.Issue{
+			"bugs": {
+				NewKeyedItem("1", "New title", "https://example.com/1", "app"),
+				NewKeyedItem("2", "Crash", "https://example.com/2", "app"),
+			},
+			"prs": {NewItem("Fix crash", "https://example.com/3", "lib")},
+		}
+	})
+
+	It("syncs all lists", func() {
+		lists := NewLists("lists", map[string]string{"Bugs": "bugs", "PRs": "prs"}, fetch, ToKeyedItem, storageClient, logrus.New())
+		Expect(lists.Sync("test")).To(Succeed())
+		Expect(storageClient.Lists).To(Equal(map[string][]issue.Issue{
+			"Bugs": {
+				NewKeyedItem("1", "Old title", "https://example.com/1", "app"),
+				NewKeyedItem("2", "Crash", "https://example.com/2", "app"),
+			},
+			"PRs": {NewItem("Fix crash", "https://example.com/3", "lib")},
+		}))
+		Expect(testutil.ToFloat64(metrics.ItemsFetched.WithLabelValues("lists", "Bugs"))).To(Equal(2.0))
+		Expect(testutil.ToFloat64(metrics.ItemsFetched.WithLabelValues("lists", "PRs"))).To(Equal(1.0))
+	})
+
+	It("syncs other lists if fetch fails", func() {
+		lists := NewLists("lists", map[string]string{"Missing": "missing", "PRs": "prs"}, fetch, ToItem, storageClient, logrus.New())
+		Expect(lists.Sync("test")).To(MatchError(errFetch))
+		Expect(storageClient.Lists["PRs"]).To(HaveLen(1))
+		Expect(storageClient.Lists).NotTo(HaveKey("Missing"))
+	})
+})
@@ -0,0 +1,115 @@
+package source
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/metrics"
+	"github.com/vrutkovs/todohub/pkg/storage"
+)
+
+// StorageBackend is a metrics label for storage API errors.
+const StorageBackend = "storage"
+
+// ConvertFunc drops storage-specific values from an existing issue.
+type ConvertFunc func(issue.Issue) issue.Issue
+
+// SyncList makes storage list match the required issues:
+// stale cards are removed and missing cards are created.
+func SyncList(sourceID, list string, storageClient storage.Client, required []issue.Issue, convert ConvertFunc, logger *logrus.Entry) error {
+	requiredList := issue.List{
+		Issues: make([]issue.Issue, 0, len(required)),
+	}
+	// Observers follow source state even if storage is unavailable
+	if observer, ok := storageClient.(storage.ListObserver); ok {
+		observer.ListSynced(sourceID, list, required)
+	}
+
+	// Create a list if its missing
+	logger.Info("fetching existing cards")
+	if err := storageClient.CreateProject(list); err != nil {
+		metrics.APIError(StorageBackend)
+		return err
+	}
+
+	// Fetch existing cards and mark all cards for removal
+	existingIssues, err := storageClient.GetIssues(list)
+	if err != nil {
+		metrics.APIError(StorageBackend)
+		return err
+	}
+	logger.WithField("count", len(existingIssues)).Info("fetched existing cards")
+	existing := issue.List{
+		Issues: make([]issue.Issue, 0, len(existingIssues)),
+	}
+
+	// Keyed items are matched by key if storage keeps keys, so that items sharing
+	// a title are kept apart and renamed items keep their cards
+	requiredKeys := make(map[string]bool)
+	for _, el := range required {
+		if key := keyOf(el); key != "" {
+			requiredKeys[key] = true
+		}
+	}
+	matched := make(map[string]bool)
+	stale := make([]issue.Issue, 0)
+	for _, el := range existingIssues {
+		key := keyOf(el)
+		// Drop internal values to make intersection work
+		el = convert(el)
+		if _, ok := el.(issue.Keyed); !ok || key == "" {
+			existing.Issues = append(existing.Issues, el)
+			continue
+		}
+		if requiredKeys[key] && !matched[key] {
+			matched[key] = true
+			continue
+		}
+		stale = append(stale, el)
+	}
+	for _, el := range required {
+		if key := keyOf(el); key == "" || !matched[key] {
+			requiredList.Issues = append(requiredList.Issues, el)
+		}
+	}
+
+	titleOnlyComparison := storageClient.CompareByTitleOnly()
+
+	// Create an intersection from these two lists
+	hashExisting := existing.MakeHashList(titleOnlyComparison)
+	hashRequired := requiredList.MakeHashList(titleOnlyComparison)
+	// Remove all cards in existing which are not in intersection
+	logger.Info("removing old cards")
+	stale = append(stale, issue.OuterSection(hashExisting, hashRequired).Issues...)
+	for _, el := range stale {
+		if err := storageClient.Delete(list, el); err != nil {
+			metrics.APIError(StorageBackend)
+			return err
+		}
+		metrics.CardsDeleted.WithLabelValues(sourceID, list).Inc()
+		logger.WithField("item", el.Title()).Info("removed")
+	}
+
+	// Add all cards from required which are not in intersection
+	logger.Info("adding new cards")
+	for _, el := range issue.OuterSection(hashRequired, hashExisting).Issues {
+		if err := storageClient.Create(list, el); err != nil {
+			metrics.APIError(StorageBackend)
+			return err
+		}
+		metrics.CardsCreated.WithLabelValues(sourceID, list).Inc()
+		logger.WithField("item", el.Title()).Info("created")
+	}
+	if err := storageClient.Sync(list); err != nil {
+		metrics.APIError(StorageBackend)
+		return err
+	}
+	return nil
+}
+
+// keyOf returns source key of the issue, empty if issue is not keyed.
+func keyOf(i issue.Issue) string {
+	if k, ok := i.(issue.Keyed); ok {
+		return k.Key()
+	}
+	return ""
+}
@@ -0,0 +1,207 @@
+package source
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/metrics"
+	"github.com/vrutkovs/todohub/pkg/storage"
+	"github.com/vrutkovs/todohub/pkg/storage/storagetest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSource(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Source")
+}
+
+type testIssue struct {
+	title string
+	url   string
+}
+
+func (i testIssue) Title() string { return i.title }
+func (i testIssue) URL() string   { return i.url }
+func (i testIssue) Repo() string  { return "" }
+
+type keyedIssue struct {
+	testIssue
+	key string
+}
+
+func (i keyedIssue) Key() string { return i.key }
+
+// storedIssue is an issue as returned by storage, with internal values.
+type storedIssue struct {
+	testIssue
+	id int
+}
+
+func dropID(i issue.Issue) issue.Issue {
+	if s, ok := i.(storedIssue); ok {
+		return s.testIssue
+	}
+	return i
+}
+
+// failingStorage fails the selected step.
+type failingStorage struct {
+	*storagetest.Storage
+	step string
+}
+
+var errStorage = errors.New("storage failed")
+
+func (s *failingStorage) fail(step string) error {
+	if s.step == step {
+		return errStorage
+	}
+	return nil
+}
+
+func (s *failingStorage) CreateProject(name string) error {
+	if err := s.fail("CreateProject"); err != nil {
+		return err
+	}
+	return s.Storage.CreateProject(name)
+}
+
+func (s *failingStorage) GetIssues(name string) ([]issue.Issue, error) {
+	if err := s.fail("GetIssues"); err != nil {
+		return nil, err
+	}
+	return s.Storage.GetIssues(name)
+}
+
+func (s *failingStorage) Create(name string, i issue.Issue) error {
+	if err := s.fail("Create"); err != nil {
+		return err
+	}
+	return s.Storage.Create(name, i)
+}
+
+func (s *failingStorage) Delete(name string, i issue.Issue) error {
+	if err := s.fail("Delete"); err != nil {
+		return err
+	}
+	return s.Storage.Delete(name, i)
+}
+
+func (s *failingStorage) Sync(name string) error {
+	return s.fail("Sync")
+}
+
+// recorder keeps synced lists.
+type recorder struct {
+	lists map[string][]issue.Issue
+}
+
+func (r *recorder) ListSynced(sourceID, list string, items []issue.Issue) {
+	r.lists[sourceID+"/"+list] = items
+}
+
+var _ = Describe("SyncList", func() {
+	const list = "To review"
+	var (
+		storageClient *failingStorage
+		logger        *logrus.Entry
+	)
+
+	counters := func() (float64, float64, float64) {
+		return testutil.ToFloat64(metrics.CardsCreated.WithLabelValues("test", list)),
+			testutil.ToFloat64(metrics.CardsDeleted.WithLabelValues("test", list)),
+			testutil.ToFloat64(metrics.APIErrors.WithLabelValues(StorageBackend))
+	}
+
+	BeforeEach(func() {
+		storageClient = &failingStorage{Storage: storagetest.New(map[string][]issue.Issue{
+			list: {
+				storedIssue{testIssue{title: "Keep", url: "https://example.com/1"}, 1},
+				storedIssue{testIssue{title: "Stale", url: "https://example.com/2"}, 2},
+			},
+		})}
+		logger = logrus.NewEntry(logrus.New())
+	})
+
+	It("creates missing and deletes stale cards", func() {
+		created, deleted, errs := counters()
+		required := []issue.Issue{
+			testIssue{title: "Keep", url: "https://example.com/1"},
+			testIssue{title: "New", url: "https://example.com/3"},
+		}
+		Expect(SyncList("test", list, storageClient, required, dropID, logger)).To(Succeed())
+		Expect(storageClient.Lists[list]).To(Equal([]issue.Issue{
+			storedIssue{testIssue{title: "Keep", url: "https://example.com/1"}, 1},
+			testIssue{title: "New", url: "https://example.com/3"},
+		}))
+
+		newCreated, newDeleted, newErrs := counters()
+		Expect(newCreated - created).To(Equal(1.0))
+		Expect(newDeleted - deleted).To(Equal(1.0))
+		Expect(newErrs).To(Equal(errs))
+	})
+
+	It("leaves unchanged list alone", func() {
+		created, deleted, _ := counters()
+		required := []issue.Issue{
+			testIssue{title: "Keep", url: "https://example.com/1"},
+			testIssue{title: "Stale", url: "https://example.com/2"},
+		}
+		Expect(SyncList("test", list, storageClient, required, dropID, logger)).To(Succeed())
+		Expect(storageClient.Lists[list]).To(HaveLen(2))
+
+		newCreated, newDeleted, _ := counters()
+		Expect(newCreated).To(Equal(created))
+		Expect(newDeleted).To(Equal(deleted))
+	})
+
+	It("creates missing list", func() {
+		Expect(SyncList("test", "New list", storageClient, []issue.Issue{testIssue{title: "New"}}, dropID, logger)).To(Succeed())
+		Expect(storageClient.Lists["New list"]).To(Equal([]issue.Issue{testIssue{title: "New"}}))
+	})
+
+	It("matches keyed items by key", func() {
+		storageClient.Lists[list] = []issue.Issue{
+			keyedIssue{testIssue{title: "Old title"}, "1"},
+			keyedIssue{testIssue{title: "Same"}, "2"},
+		}
+		required := []issue.Issue{
+			keyedIssue{testIssue{title: "New title"}, "1"},
+			keyedIssue{testIssue{title: "Same"}, "3"},
+		}
+		Expect(SyncList("test", list, storageClient, required, dropID, logger)).To(Succeed())
+		Expect(storageClient.Lists[list]).To(Equal([]issue.Issue{
+			keyedIssue{testIssue{title: "Old title"}, "1"},
+			keyedIssue{testIssue{title: "Same"}, "3"},
+		}))
+	})
+
+	It("notifies storage observers even if storage fails", func() {
+		r := &recorder{lists: make(map[string][]issue.Issue)}
+		storageClient.step = "CreateProject"
+		required := []issue.Issue{testIssue{title: "New"}}
+		Expect(SyncList("test", list, storage.NewObserved(storageClient, r), required, dropID, logger)).To(MatchError(errStorage))
+		Expect(r.lists).To(Equal(map[string][]issue.Issue{"test/" + list: required}))
+	})
+
+	DescribeTable("returns storage errors",
+		func(step string) {
+			_, _, errs := counters()
+			storageClient.step = step
+			required := []issue.Issue{testIssue{title: "New"}}
+			Expect(SyncList("test", list, storageClient, required, dropID, logger)).To(MatchError(errStorage))
+			_, _, newErrs := counters()
+			Expect(newErrs - errs).To(Equal(1.0))
+		},
+		Entry("CreateProject", "CreateProject"),
+		Entry("GetIssues", "GetIssues"),
+		Entry("Delete", "Delete"),
+		Entry("Create", "Create"),
+		Entry("Sync", "Sync"),
+	)
+})
@@ -0,0 +1,319 @@
+package caldav
+
+import (
+	"bytes"
+	"crypto/sha1" //nolint:gosec
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/backoff"
+	"github.com/vrutkovs/todohub/pkg/ical"
+	"github.com/vrutkovs/todohub/pkg/issue"
+)
+
+// ProdID identifies todohub in created calendar objects.
+const ProdID = "-//todohub//todohub//EN"
+
+// removedProperty marks tasks completed by todohub, so that they are no longer listed.
+const removedProperty = "X-TODOHUB-REMOVED"
+
+// keyProperty holds source key of the task.
+const keyProperty = "X-TODOHUB-KEY"
+
+const (
+	statusNeedsAction = "NEEDS-ACTION"
+	statusCompleted   = "COMPLETED"
+)
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Client is a CalDAV task list client.
+type Client struct {
+	http     *http.Client
+	endpoint *url.URL
+	settings *Settings
+	// mu guards calendars, which are shared by concurrent source syncs
+	mu        sync.Mutex
+	calendars map[string]string
+	logger    *logrus.Logger
+}
+
+// Item struct holds information about the task.
+type Item struct {
+	key   string
+	title string
+	url   string
+	repo  string
+}
+
+func (i Item) Title() string {
+	return i.title
+}
+
+func (i Item) URL() string {
+	return i.url
+}
+
+func (i Item) Repo() string {
+	return i.repo
+}
+
+// Key returns source key of the task.
+func (i Item) Key() string {
+	return i.key
+}
+
+// todo is a stored VTODO with its location.
+type todo struct {
+	href     string
+	etag     string
+	calendar *ical.Component
+	vtodo    *ical.Component
+}
+
+// New returns CalDAV client.
+func New(s *Settings, logger *logrus.Logger) (*Client, error) {
+	endpoint, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(endpoint.Path, "/") {
+		endpoint.Path += "/"
+	}
+	c := &Client{
+		http:     backoff.NewClient(s.Retry.Policy()),
+		endpoint: endpoint,
+		settings: s,
+		logger:   logger,
+	}
+	if err := c.discoverCalendars(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// discoverCalendars maps calendar display names to collection URLs.
+func (c *Client) discoverCalendars() error {
+	responses, err := c.multistatus(methodPropfind, c.endpoint.String(), propfindCalendars, "1")
+	if err != nil {
+		return err
+	}
+	calendars := make(map[string]string)
+	for _, r := range responses {
+		p, ok := r.prop()
+		if !ok || p.ResourceType.Calendar == nil || p.DisplayName == "" {
+			continue
+		}
+		href, err := c.resolve(r.Href)
+		if err != nil {
+			return err
+		}
+		calendars[p.DisplayName] = href
+	}
+	c.calendars = calendars
+	return nil
+}
+
+// slug builds collection name from list name.
+func slug(name string) string {
+	s := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if s == "" {
+		sum := sha1.Sum([]byte(name)) //nolint:gosec
+		s = hex.EncodeToString(sum[:8])
+	}
+	return s
+}
+
+// CreateProject creates a task calendar for the list if its missing.
+func (c *Client) CreateProject(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.calendars[name]; ok {
+		return nil
+	}
+	c.logger.WithFields(logrus.Fields{"storage": "caldav", "list": name}).Info("creating calendar")
+	var displayName bytes.Buffer
+	if err := xml.EscapeText(&displayName, []byte(name)); err != nil {
+		return err
+	}
+	target := c.endpoint.JoinPath(slug(name)).String() + "/"
+	resp, err := c.do(methodMkcalendar, target, fmt.Sprintf(mkcalendarTemplate, displayName.String()),
+		map[string]string{"Content-Type": "application/xml; charset=utf-8"}, http.StatusCreated)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	c.calendars[name] = target
+	return nil
+}
+
+// calendar returns collection URL for the list.
+func (c *Client) calendar(listName string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	target, ok := c.calendars[listName]
+	if !ok {
+		return "", fmt.Errorf("caldav: calendar %q not found", listName)
+	}
+	return target, nil
+}
+
+// fetchTodos returns tasks in the list.
+// Tasks completed by todohub are skipped, tasks completed by user are kept,
+// so that sources don't recreate them.
+func (c *Client) fetchTodos(listName string) ([]todo, error) {
+	target, err := c.calendar(listName)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := c.multistatus(methodReport, target, reportTodos, "1")
+	if err != nil {
+		return nil, err
+	}
+	results := make([]todo, 0)
+	for _, r := range responses {
+		p, ok := r.prop()
+		if !ok || p.CalendarData == "" {
+			continue
+		}
+		cal, err := ical.Decode(strings.NewReader(p.CalendarData))
+		if err != nil {
+			c.logger.WithFields(logrus.Fields{"storage": "caldav", "href": r.Href}).WithError(err).Warn("skipping invalid calendar object")
+			continue
+		}
+		href, err := c.resolve(r.Href)
+		if err != nil {
+			return nil, err
+		}
+		for _, vtodo := range cal.Find("VTODO") {
+			if vtodo.Get(removedProperty) != "" {
+				continue
+			}
+			results = append(results, todo{href: href, etag: p.ETag, calendar: cal, vtodo: vtodo})
+		}
+	}
+	return results, nil
+}
+
+func (c *Client) GetIssues(listName string) ([]issue.Issue, error) {
+	todos, err := c.fetchTodos(listName)
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]issue.Issue, len(todos))
+	for i, t := range todos {
+		item := Item{
+			key:   t.vtodo.Get(keyProperty),
+			title: t.vtodo.Get("SUMMARY"),
+			url:   t.vtodo.Get("URL"),
+		}
+		if categories := t.vtodo.GetList("CATEGORIES"); len(categories) > 0 {
+			item.repo = categories[0]
+		}
+		issues[i] = item
+	}
+	return issues, nil
+}
+
+// uid returns stable task UID for the issue key.
+func uid(item issue.Issue) string {
+	key := issue.Key(item)
+	if key == "" {
+		key = item.Title()
+	}
+	sum := sha1.Sum([]byte(key)) //nolint:gosec
+	return hex.EncodeToString(sum[:]) + "@todohub"
+}
+
+// Create puts a new VTODO into list calendar.
+// Previously completed task with the same UID is reopened.
+func (c *Client) Create(listName string, item issue.Issue) error {
+	target, err := c.calendar(listName)
+	if err != nil {
+		return err
+	}
+	id := uid(item)
+	now := time.Now()
+	vtodo := &ical.Component{Name: "VTODO"}
+	vtodo.SetRaw("UID", id)
+	if key := issue.Key(item); key != "" {
+		vtodo.Set(keyProperty, key)
+	}
+	vtodo.SetTime("DTSTAMP", now)
+	vtodo.SetTime("CREATED", now)
+	vtodo.Set("SUMMARY", item.Title())
+	vtodo.SetRaw("STATUS", statusNeedsAction)
+	if item.URL() != "" {
+		vtodo.SetRaw("URL", item.URL())
+	}
+	if item.Repo() != "" {
+		vtodo.SetList("CATEGORIES", []string{item.Repo()})
+	}
+	if description := issue.Description(item); description != "" {
+		vtodo.Set("DESCRIPTION", description)
+	}
+	cal := ical.NewCalendar(ProdID)
+	cal.Children = append(cal.Children, vtodo)
+
+	resp, err := c.do(http.MethodPut, target+url.PathEscape(id)+".ics", cal.String(),
+		map[string]string{"Content-Type": "text/calendar; charset=utf-8"},
+		http.StatusCreated, http.StatusNoContent, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Delete marks matching tasks completed instead of removing them.
+func (c *Client) Delete(listName string, item issue.Issue) error {
+	todos, err := c.fetchTodos(listName)
+	if err != nil {
+		return err
+	}
+	for _, t := range todos {
+		if !issue.Matches(item, t.vtodo.Get(keyProperty), t.vtodo.Get("SUMMARY")) {
+			continue
+		}
+		now := time.Now()
+		if t.vtodo.Get("STATUS") != statusCompleted {
+			t.vtodo.SetRaw("STATUS", statusCompleted)
+			t.vtodo.SetTime("COMPLETED", now)
+			t.vtodo.SetRaw("PERCENT-COMPLETE", "100")
+		}
+		t.vtodo.SetTime("DTSTAMP", now)
+		t.vtodo.SetTime("LAST-MODIFIED", now)
+		t.vtodo.SetRaw(removedProperty, "TRUE")
+
+		headers := map[string]string{"Content-Type": "text/calendar; charset=utf-8"}
+		if t.etag != "" {
+			headers["If-Match"] = t.etag
+		}
+		resp, err := c.do(http.MethodPut, t.href, t.calendar.String(), headers, http.StatusNoContent, http.StatusOK, http.StatusCreated)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+	}
+	return nil
+}
+
+// Sync ensures changes are committed.
+func (c *Client) Sync(_ string) error {
+	// tasks are written immediately
+	return nil
+}
+
+// CompareByTitleOnly returns true if issues should be compared by title only
+// Some storages may not be able to fetch other details like URL in GetIssues.
+func (c *Client) CompareByTitleOnly() bool {
+	return true
+}
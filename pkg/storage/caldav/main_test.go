@@ -0,0 +1,211 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/ical"
+	"github.com/vrutkovs/todohub/pkg/issue"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCaldav(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CalDAV")
+}
+
+type testIssue struct {
+	title string
+	url   string
+	repo  string
+}
+
+func (i testIssue) Title() string { return i.title }
+func (i testIssue) URL() string   { return i.url }
+func (i testIssue) Repo() string  { return i.repo }
+
+var displayNameRe = regexp.MustCompile(`<d:displayname>(.*)</d:displayname>`)
+
+// fakeServer is an in-process CalDAV server keeping calendars in memory.
+type fakeServer struct {
+	mu        sync.Mutex
+	calendars map[string]string            // path -> display name
+	objects   map[string]map[string]string // calendar path -> object name -> data
+	created   int
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	body, err := io.ReadAll(r.Body)
+	Expect(err).NotTo(HaveOccurred())
+	user, password, _ := r.BasicAuth()
+	if user != "user" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case methodPropfind:
+		Expect(r.URL.Path).To(Equal("/calendars/user/"))
+		responses := ""
+		for path, name := range f.calendars {
+			responses += fmt.Sprintf(`<d:response><d:href>%s</d:href><d:propstat><d:prop><d:displayname>%s</d:displayname>
+				<d:resourcetype><d:collection/><c:calendar/></d:resourcetype></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`, path, name)
+		}
+		multistatusResponse(w, responses)
+	case methodMkcalendar:
+		name := displayNameRe.FindStringSubmatch(string(body))
+		Expect(name).To(HaveLen(2))
+		f.calendars[r.URL.Path] = name[1]
+		f.objects[r.URL.Path] = map[string]string{}
+		f.created++
+		w.WriteHeader(http.StatusCreated)
+	case methodReport:
+		objects, ok := f.objects[r.URL.Path]
+		Expect(ok).To(BeTrue())
+		responses := ""
+		for name, data := range objects {
+			var escaped strings.Builder
+			Expect(xml.EscapeText(&escaped, []byte(data))).To(Succeed())
+			responses += fmt.Sprintf(`<d:response><d:href>%s%s</d:href><d:propstat><d:prop><d:getetag>"%d"</d:getetag>
+				<c:calendar-data>%s</c:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`,
+				r.URL.Path, name, len(data), escaped.String())
+		}
+		multistatusResponse(w, responses)
+	case http.MethodPut:
+		idx := strings.LastIndex(r.URL.Path, "/")
+		objects, ok := f.objects[r.URL.Path[:idx+1]]
+		Expect(ok).To(BeTrue())
+		name := r.URL.Path[idx+1:]
+		if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && ifMatch != fmt.Sprintf(`"%d"`, len(objects[name])) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		objects[name] = string(body)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func multistatusResponse(w http.ResponseWriter, responses string) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	fmt.Fprintf(w, `<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">%s</d:multistatus>`, responses)
+}
+
+var _ = DescribeTable("slug",
+	func(name, expected string) {
+		Expect(slug(name)).To(Equal(expected))
+	},
+	Entry("Spaces", "To review", "to-review"),
+	Entry("Punctuation", "  PRs: mine!", "prs-mine"),
+	Entry("Non-latin", "Задачи", "2ff08344934d2f5c"),
+)
+
+var _ = Describe("Client", func() {
+	var (
+		fake   *fakeServer
+		srv    *httptest.Server
+		client *Client
+	)
+
+	BeforeEach(func() {
+		fake = &fakeServer{
+			calendars: map[string]string{"/calendars/user/personal/": "Personal"},
+			objects:   map[string]map[string]string{"/calendars/user/personal/": {}},
+		}
+		srv = httptest.NewServer(fake)
+		var err error
+		client, err = New(&Settings{Endpoint: srv.URL + "/calendars/user", Username: "user", Password: "secret"}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		srv.Close()
+	})
+
+	It("discovers existing calendars and creates missing ones", func() {
+		Expect(client.CreateProject("Personal")).To(Succeed())
+		Expect(client.CreateProject("To review & merge")).To(Succeed())
+		Expect(fake.calendars).To(HaveKeyWithValue("/calendars/user/to-review-merge/", "To review &amp; merge"))
+		Expect(client.calendars).To(HaveKeyWithValue("To review & merge", srv.URL+"/calendars/user/to-review-merge/"))
+	})
+
+	It("creates tasks with stable UID", func() {
+		Expect(client.CreateProject("To review")).To(Succeed())
+		item := testIssue{title: "Fix, then ship", url: "https://github.com/o/r/pull/1", repo: "o/r"}
+		Expect(client.Create("To review", item)).To(Succeed())
+
+		issues, err := client.GetIssues("To review")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(ConsistOf(issue.Issue(Item{key: "https://github.com/o/r/pull/1", title: "Fix, then ship", url: "https://github.com/o/r/pull/1", repo: "o/r"})))
+		Expect(uid(item)).To(Equal(uid(testIssue{title: "Renamed", url: "https://github.com/o/r/pull/1"})))
+	})
+
+	It("marks tasks completed instead of deleting", func() {
+		Expect(client.CreateProject("To review")).To(Succeed())
+		item := testIssue{title: "Fix", url: "https://github.com/o/r/pull/1"}
+		Expect(client.Create("To review", item)).To(Succeed())
+		Expect(client.Delete("To review", item)).To(Succeed())
+
+		Expect(client.GetIssues("To review")).To(BeEmpty())
+		objects := fake.objects["/calendars/user/to-review/"]
+		Expect(objects).To(HaveLen(1))
+		for _, data := range objects {
+			cal, err := ical.Decode(strings.NewReader(data))
+			Expect(err).NotTo(HaveOccurred())
+			vtodo := cal.Find("VTODO")[0]
+			Expect(vtodo.Get("STATUS")).To(Equal(statusCompleted))
+			Expect(vtodo.Get("COMPLETED")).NotTo(BeEmpty())
+		}
+
+		// Reopened when the source requires it again
+		Expect(client.Create("To review", item)).To(Succeed())
+		Expect(client.GetIssues("To review")).To(HaveLen(1))
+		Expect(objects).To(HaveLen(1))
+	})
+
+	It("keeps tasks completed by user", func() {
+		Expect(client.CreateProject("To review")).To(Succeed())
+		Expect(client.Create("To review", testIssue{title: "Fix"})).To(Succeed())
+		objects := fake.objects["/calendars/user/to-review/"]
+		for name, data := range objects {
+			objects[name] = strings.Replace(data, "STATUS:NEEDS-ACTION", "STATUS:COMPLETED", 1)
+		}
+		Expect(client.GetIssues("To review")).To(HaveLen(1))
+	})
+
+	It("creates calendars once for concurrent syncs", func() {
+		var wg sync.WaitGroup
+		for n := range 4 {
+			wg.Add(1)
+			go func(n int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				list := fmt.Sprintf("List %d", n%2)
+				Expect(client.CreateProject(list)).To(Succeed())
+				Expect(client.Create(list, testIssue{title: fmt.Sprintf("Fix %d", n)})).To(Succeed())
+				Expect(client.GetIssues(list)).NotTo(BeEmpty())
+			}(n)
+		}
+		wg.Wait()
+
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		Expect(fake.created).To(Equal(2))
+		Expect(fake.objects["/calendars/user/list-0/"]).To(HaveLen(2))
+		Expect(fake.objects["/calendars/user/list-1/"]).To(HaveLen(2))
+	})
+})
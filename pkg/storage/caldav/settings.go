@@ -0,0 +1,22 @@
+package caldav
+
+import "github.com/vrutkovs/todohub/pkg/backoff"
+
+// Settings holds info about CalDAV connection.
+type Settings struct {
+	// Endpoint is a calendar home collection URL,
+	// e.g. https://cloud.example.com/remote.php/dav/calendars/username/
+	Endpoint string            `yaml:"endpoint"`
+	Username string            `yaml:"username"`
+	Password string            `yaml:"password"`
+	Retry    *backoff.Settings `yaml:"retry,omitempty"`
+}
+
+// Implement storage.Settings.
+func (s Settings) ID() string {
+	return "caldav"
+}
+
+func (s Settings) Project() string {
+	return s.Endpoint
+}
@@ -0,0 +1,137 @@
+package caldav
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	methodPropfind   = "PROPFIND"
+	methodReport     = "REPORT"
+	methodMkcalendar = "MKCALENDAR"
+)
+
+const propfindCalendars = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop>
+    <d:displayname/>
+    <d:resourcetype/>
+  </d:prop>
+</d:propfind>`
+
+const reportTodos = `<?xml version="1.0" encoding="utf-8"?>
+<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop>
+    <d:getetag/>
+    <c:calendar-data/>
+  </d:prop>
+  <c:filter>
+    <c:comp-filter name="VCALENDAR">
+      <c:comp-filter name="VTODO"/>
+    </c:comp-filter>
+  </c:filter>
+</c:calendar-query>`
+
+const mkcalendarTemplate = `<?xml version="1.0" encoding="utf-8"?>
+<c:mkcalendar xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:set>
+    <d:prop>
+      <d:displayname>%s</d:displayname>
+      <c:supported-calendar-component-set>
+        <c:comp name="VTODO"/>
+      </c:supported-calendar-component-set>
+    </d:prop>
+  </d:set>
+</c:mkcalendar>`
+
+// multistatus is a WebDAV multi-status response.
+type multistatus struct {
+	XMLName   xml.Name   `xml:"DAV: multistatus"`
+	Responses []response `xml:"DAV: response"`
+}
+
+type response struct {
+	Href      string     `xml:"DAV: href"`
+	Propstats []propstat `xml:"DAV: propstat"`
+}
+
+type propstat struct {
+	Status string `xml:"DAV: status"`
+	Prop   prop   `xml:"DAV: prop"`
+}
+
+type prop struct {
+	DisplayName  string `xml:"DAV: displayname"`
+	ETag         string `xml:"DAV: getetag"`
+	CalendarData string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+	ResourceType struct {
+		Calendar *struct{} `xml:"urn:ietf:params:xml:ns:caldav calendar"`
+	} `xml:"DAV: resourcetype"`
+}
+
+// prop returns properties from the successful propstat.
+func (r response) prop() (prop, bool) {
+	for _, ps := range r.Propstats {
+		if ps.Status == "" || strings.Contains(ps.Status, " 200 ") {
+			return ps.Prop, true
+		}
+	}
+	return prop{}, false
+}
+
+// do sends authenticated request and returns response if status is expected.
+func (c *Client) do(method, target string, body string, headers map[string]string, expected ...int) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(context.Background(), method, target, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if c.settings.Username != "" {
+		req.SetBasicAuth(c.settings.Username, c.settings.Password)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			return resp, nil
+		}
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("caldav: %s %s: %s: %s", method, target, resp.Status, strings.TrimSpace(string(msg)))
+}
+
+// multistatus sends PROPFIND or REPORT request and decodes the response.
+func (c *Client) multistatus(method, target, body, depth string) ([]response, error) {
+	resp, err := c.do(method, target, body, map[string]string{
+		"Content-Type": "application/xml; charset=utf-8",
+		"Depth":        depth,
+	}, http.StatusMultiStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var ms multistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("caldav: failed to parse %s response: %w", method, err)
+	}
+	return ms.Responses, nil
+}
+
+// resolve returns absolute URL for href from multistatus response.
+func (c *Client) resolve(href string) (string, error) {
+	u, err := url.Parse(href)
+	if err != nil {
+		return "", err
+	}
+	return c.endpoint.ResolveReference(u).String(), nil
+}
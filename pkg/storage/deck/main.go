@@ -0,0 +1,268 @@
+package deck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/backoff"
+	"github.com/vrutkovs/todohub/pkg/issue"
+)
+
+// Client is a wrapper for Nextcloud Deck REST API.
+type Client struct {
+	http     *http.Client
+	endpoint *url.URL
+	settings *Settings
+	logger   *logrus.Logger
+}
+
+// Card struct holds information about the card.
+type Card struct {
+	id    int
+	title string
+	url   string
+}
+
+func (c Card) Title() string {
+	return c.title
+}
+
+// URL returns link from the first line of card description.
+func (c Card) URL() string {
+	return c.url
+}
+
+func (c Card) Repo() string {
+	return ""
+}
+
+type stack struct {
+	ID    int        `json:"id"`
+	Title string     `json:"title"`
+	Order int        `json:"order"`
+	Cards []deckCard `json:"cards"`
+}
+
+type deckCard struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Archived    bool   `json:"archived"`
+}
+
+// New returns Deck client.
+func New(s *Settings, logger *logrus.Logger) (*Client, error) {
+	endpoint, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		http:     backoff.NewClient(s.Retry.Policy()),
+		endpoint: endpoint,
+		settings: s,
+		logger:   logger,
+	}
+	// Check that board is accessible
+	if err := c.do(http.MethodGet, c.boardPath(), nil, &struct{}{}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Client) boardPath() string {
+	return "boards/" + strconv.Itoa(c.settings.BoardID)
+}
+
+// stacks returns board stacks with their cards.
+func (c *Client) stacks() ([]stack, error) {
+	var stacks []stack
+	err := c.do(http.MethodGet, c.boardPath()+"/stacks", nil, &stacks)
+	return stacks, err
+}
+
+// errStackNotFound is returned by findStack if board has no stack for the list.
+var errStackNotFound = errors.New("deck: stack not found")
+
+// findStack returns stack by list name.
+func (c *Client) findStack(name string) (*stack, error) {
+	stacks, err := c.stacks()
+	if err != nil {
+		return nil, err
+	}
+	for i := range stacks {
+		if stacks[i].Title == name {
+			return &stacks[i], nil
+		}
+	}
+	return nil, errStackNotFound
+}
+
+// ensureStackExists returns stack for the list, creating it if its missing.
+func (c *Client) ensureStackExists(name string) (*stack, error) {
+	stacks, err := c.stacks()
+	if err != nil {
+		return nil, err
+	}
+	order := 0
+	for i := range stacks {
+		if stacks[i].Title == name {
+			return &stacks[i], nil
+		}
+		if stacks[i].Order >= order {
+			order = stacks[i].Order + 1
+		}
+	}
+	c.logger.WithFields(logrus.Fields{"storage": "deck", "list": name}).Info("creating stack")
+	var created stack
+	err = c.do(http.MethodPost, c.boardPath()+"/stacks", map[string]interface{}{
+		"title": name,
+		"order": order,
+	}, &created)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (c *Client) CreateProject(name string) error {
+	_, err := c.ensureStackExists(name)
+	return err
+}
+
+func (c *Client) GetIssues(listName string) ([]issue.Issue, error) {
+	issues := make([]issue.Issue, 0)
+	s, err := c.findStack(listName)
+	if errors.Is(err, errStackNotFound) {
+		return issues, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, card := range s.Cards {
+		if card.Archived {
+			continue
+		}
+		issues = append(issues, Card{
+			id:    card.ID,
+			title: card.Title,
+			url:   descriptionURL(card.Description),
+		})
+	}
+	return issues, nil
+}
+
+// descriptionURL returns link from the first description line.
+func descriptionURL(description string) string {
+	line, _, _ := strings.Cut(description, "\n")
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "http://") || strings.HasPrefix(line, "https://") {
+		return line
+	}
+	return ""
+}
+
+// cardDescription keeps issue link on the first line followed by issue details.
+func cardDescription(item issue.Issue) string {
+	parts := make([]string, 0, 2)
+	if item.URL() != "" {
+		parts = append(parts, item.URL())
+	}
+	if description := issue.Description(item); description != "" {
+		parts = append(parts, description)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func (c *Client) Create(listName string, item issue.Issue) error {
+	s, err := c.ensureStackExists(listName)
+	if err != nil {
+		return err
+	}
+	return c.do(http.MethodPost, fmt.Sprintf("%s/stacks/%d/cards", c.boardPath(), s.ID), map[string]interface{}{
+		"title":       item.Title(),
+		"type":        "plain",
+		"order":       len(s.Cards),
+		"description": cardDescription(item),
+	}, nil)
+}
+
+// Delete archives cards with matching title.
+func (c *Client) Delete(listName string, item issue.Issue) error {
+	s, err := c.findStack(listName)
+	if errors.Is(err, errStackNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, card := range s.Cards {
+		if card.Archived || card.Title != item.Title() {
+			continue
+		}
+		if err := c.do(http.MethodPut, fmt.Sprintf("%s/stacks/%d/cards/%d/archive", c.boardPath(), s.ID, card.ID), nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sync ensures changes are committed.
+func (c *Client) Sync(_ string) error {
+	// deck changes are auto-synced
+	return nil
+}
+
+// CompareByTitleOnly returns true if issues should be compared by title only
+// Some storages may not be able to fetch other details like URL in GetIssues.
+func (c *Client) CompareByTitleOnly() bool {
+	return true
+}
+
+// do sends authenticated request to Deck API and decodes JSON response.
+func (c *Client) do(method, path string, body, result interface{}) error {
+	u := c.endpoint.JoinPath("index.php", "apps", "deck", "api", "v1.0", path)
+	var reqBody io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(context.Background(), method, u.String(), reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("OCS-APIRequest", "true")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(c.settings.Username, c.settings.Password)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("deck: %s %s: %s: %s", method, u.Path, resp.Status, apiErr.Message)
+		}
+		return fmt.Errorf("deck: %s %s: %s", method, u.Path, resp.Status)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,188 @@
+package deck
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestDeck(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Deck")
+}
+
+type testIssue struct {
+	title       string
+	url         string
+	description string
+}
+
+func (i testIssue) Title() string       { return i.title }
+func (i testIssue) URL() string         { return i.url }
+func (i testIssue) Repo() string        { return "" }
+func (i testIssue) Description() string { return i.description }
+
+// fakeServer keeps a single Deck board in memory.
+type fakeServer struct {
+	mu     sync.Mutex
+	nextID int
+	stacks []stack
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	user, password, _ := r.BasicAuth()
+	if user != "user" || password != "secret" || r.Header.Get("OCS-APIRequest") != "true" {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"message":"Current user is not logged in"}`)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/index.php/apps/deck/api/v1.0/")
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || parts[0] != "boards" || parts[1] != "7" {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message":"Permission denied"}`)
+		return
+	}
+	var result interface{}
+	switch {
+	case r.Method == http.MethodGet && len(parts) == 2:
+		result = map[string]interface{}{"id": 7, "title": "Work"}
+	case r.Method == http.MethodGet && len(parts) == 3:
+		// Deck omits archived cards from stacks listing
+		stacks := make([]stack, len(f.stacks))
+		for i, s := range f.stacks {
+			stacks[i] = s
+			stacks[i].Cards = nil
+			for _, c := range s.Cards {
+				if !c.Archived {
+					stacks[i].Cards = append(stacks[i].Cards, c)
+				}
+			}
+		}
+		result = stacks
+	case r.Method == http.MethodPost && len(parts) == 3:
+		var s stack
+		Expect(json.NewDecoder(r.Body).Decode(&s)).To(Succeed())
+		f.nextID++
+		s.ID = f.nextID
+		f.stacks = append(f.stacks, s)
+		result = s
+	case r.Method == http.MethodPost && len(parts) == 5:
+		var body map[string]interface{}
+		Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+		Expect(body).To(HaveKeyWithValue("type", "plain"))
+		stackID, _ := strconv.Atoi(parts[3])
+		f.nextID++
+		card := deckCard{ID: f.nextID, Title: body["title"].(string), Description: body["description"].(string)}
+		for i := range f.stacks {
+			if f.stacks[i].ID == stackID {
+				f.stacks[i].Cards = append(f.stacks[i].Cards, card)
+			}
+		}
+		result = card
+	case r.Method == http.MethodPut && len(parts) == 7 && parts[6] == "archive":
+		cardID, _ := strconv.Atoi(parts[5])
+		for i := range f.stacks {
+			for j := range f.stacks[i].Cards {
+				if f.stacks[i].Cards[j].ID == cardID {
+					f.stacks[i].Cards[j].Archived = true
+					result = f.stacks[i].Cards[j]
+				}
+			}
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"Not found"}`)
+		return
+	}
+	Expect(json.NewEncoder(w).Encode(result)).To(Succeed())
+}
+
+var _ = Describe("Client", func() {
+	var (
+		fake *fakeServer
+		srv  *httptest.Server
+	)
+
+	BeforeEach(func() {
+		fake = &fakeServer{nextID: 100, stacks: []stack{{ID: 1, Title: "Backlog", Order: 3}}}
+		srv = httptest.NewServer(fake)
+	})
+
+	AfterEach(func() {
+		srv.Close()
+	})
+
+	It("creates stacks once", func() {
+		client, err := New(&Settings{Endpoint: srv.URL, Username: "user", Password: "secret", BoardID: 7}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.CreateProject("To review")).To(Succeed())
+		Expect(client.CreateProject("To review")).To(Succeed())
+		Expect(client.CreateProject("Backlog")).To(Succeed())
+		Expect(fake.stacks).To(Equal([]stack{
+			{ID: 1, Title: "Backlog", Order: 3},
+			{ID: 101, Title: "To review", Order: 4},
+		}))
+	})
+
+	It("creates, lists and archives cards", func() {
+		client, err := New(&Settings{Endpoint: srv.URL, Username: "user", Password: "secret", BoardID: 7}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Create("Backlog", testIssue{title: "Fix", url: "https://github.com/o/r/pull/1", description: "Failing checks:\n- ci"})).To(Succeed())
+		Expect(client.Create("Backlog", testIssue{title: "Bump", url: "https://github.com/o/r/pull/2"})).To(Succeed())
+		Expect(fake.stacks[0].Cards[0].Description).To(Equal("https://github.com/o/r/pull/1\n\nFailing checks:\n- ci"))
+
+		issues, err := client.GetIssues("Backlog")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(ConsistOf(
+			issue.Issue(Card{id: 101, title: "Fix", url: "https://github.com/o/r/pull/1"}),
+			issue.Issue(Card{id: 102, title: "Bump", url: "https://github.com/o/r/pull/2"}),
+		))
+
+		Expect(client.Delete("Backlog", testIssue{title: "Fix"})).To(Succeed())
+		Expect(fake.stacks[0].Cards[0].Archived).To(BeTrue())
+		issues, err = client.GetIssues("Backlog")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0].Title()).To(Equal("Bump"))
+	})
+
+	It("returns no issues for missing stack", func() {
+		client, err := New(&Settings{Endpoint: srv.URL, Username: "user", Password: "secret", BoardID: 7}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		issues, err := client.GetIssues("Missing")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(BeEmpty())
+		Expect(client.Delete("Missing", testIssue{title: "Fix"})).To(Succeed())
+		_, err = client.findStack("Missing")
+		Expect(err).To(MatchError(errStackNotFound))
+	})
+
+	It("returns API error message", func() {
+		_, err := New(&Settings{Endpoint: srv.URL, Username: "user", Password: "secret", BoardID: 8}, logrus.New())
+		Expect(err).To(MatchError(ContainSubstring("Permission denied")))
+	})
+
+	DescribeTable("descriptionURL",
+		func(description, expected string) {
+			Expect(descriptionURL(description)).To(Equal(expected))
+		},
+		Entry("link only", "https://example.com/1", "https://example.com/1"),
+		Entry("link with details", "https://example.com/1\n\ndetails", "https://example.com/1"),
+		Entry("no link", "some notes\nhttps://example.com/1", ""),
+		Entry("empty", "", ""),
+	)
+})
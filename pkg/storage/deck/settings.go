@@ -0,0 +1,27 @@
+package deck
+
+import (
+	"strconv"
+
+	"github.com/vrutkovs/todohub/pkg/backoff"
+)
+
+// Settings holds info about Nextcloud Deck connection.
+type Settings struct {
+	// Endpoint is Nextcloud URL, e.g. https://cloud.example.com
+	Endpoint string `yaml:"endpoint"`
+	Username string `yaml:"username"`
+	// Password is an app password.
+	Password string            `yaml:"password"`
+	BoardID  int               `yaml:"board_id"`
+	Retry    *backoff.Settings `yaml:"retry,omitempty"`
+}
+
+// Implement storage.Settings.
+func (s Settings) ID() string {
+	return "deck"
+}
+
+func (s Settings) Project() string {
+	return strconv.Itoa(s.BoardID)
+}
@@ -0,0 +1,210 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+)
+
+// Record events.
+const (
+	EventListCreated = "list_created"
+	EventCreated     = "created"
+	EventDeleted     = "deleted"
+)
+
+// Record is a single line of export file.
+type Record struct {
+	Time  time.Time `json:"time"`
+	Event string    `json:"event"`
+	List  string    `json:"list"`
+	Title string    `json:"title,omitempty"`
+	URL   string    `json:"url,omitempty"`
+	Repo  string    `json:"repo,omitempty"`
+	Key   string    `json:"key,omitempty"`
+}
+
+// Client appends storage changes to newline-delimited JSON file.
+// Current lists are rebuilt by replaying the file on start.
+type Client struct {
+	settings *Settings
+	mu       sync.Mutex
+	lists    map[string][]Item
+	now      func() time.Time
+	logger   *logrus.Logger
+}
+
+// Item struct holds information about the exported item.
+type Item struct {
+	key   string
+	title string
+	url   string
+	repo  string
+}
+
+func (i Item) Title() string {
+	return i.title
+}
+
+func (i Item) URL() string {
+	return i.url
+}
+
+func (i Item) Repo() string {
+	return i.repo
+}
+
+// Key returns source key of the item.
+func (i Item) Key() string {
+	return i.key
+}
+
+// New returns export client.
+func New(s *Settings, logger *logrus.Logger) (*Client, error) {
+	if s.Path == "" {
+		return nil, errors.New("export: path is not set")
+	}
+	c := &Client{
+		settings: s,
+		lists:    make(map[string][]Item),
+		now:      time.Now,
+		logger:   logger,
+	}
+	if err := c.replay(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// replay rebuilds lists from recorded events.
+func (c *Client) replay() error {
+	f, err := os.Open(c.settings.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return fmt.Errorf("export: %s:%d: %w", c.settings.Path, line, err)
+		}
+		c.apply(r)
+	}
+	return scanner.Err()
+}
+
+// apply updates in-memory lists with the record.
+func (c *Client) apply(r Record) {
+	switch r.Event {
+	case EventListCreated:
+		if _, ok := c.lists[r.List]; !ok {
+			c.lists[r.List] = []Item{}
+		}
+	case EventCreated:
+		c.lists[r.List] = append(c.lists[r.List], Item{key: r.Key, title: r.Title, url: r.URL, repo: r.Repo})
+	case EventDeleted:
+		items := make([]Item, 0, len(c.lists[r.List]))
+		for _, i := range c.lists[r.List] {
+			if !issue.Matches(i, r.Key, r.Title) {
+				items = append(items, i)
+			}
+		}
+		c.lists[r.List] = items
+	}
+}
+
+// record appends the record to export file and applies it.
+func (c *Client) record(r Record) error {
+	r.Time = c.now().UTC()
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(c.settings.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	c.apply(r)
+	return nil
+}
+
+func (c *Client) CreateProject(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.lists[name]; ok {
+		return nil
+	}
+	return c.record(Record{Event: EventListCreated, List: name})
+}
+
+func (c *Client) GetIssues(listName string) ([]issue.Issue, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	issues := make([]issue.Issue, len(c.lists[listName]))
+	for i, item := range c.lists[listName] {
+		issues[i] = item
+	}
+	return issues, nil
+}
+
+func (c *Client) Create(listName string, item issue.Issue) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.record(Record{
+		Event: EventCreated,
+		List:  listName,
+		Title: item.Title(),
+		URL:   item.URL(),
+		Repo:  item.Repo(),
+		Key:   issue.Key(item),
+	})
+}
+
+func (c *Client) Delete(listName string, item issue.Issue) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.record(Record{
+		Event: EventDeleted,
+		List:  listName,
+		Title: item.Title(),
+		URL:   item.URL(),
+		Repo:  item.Repo(),
+		Key:   issue.Key(item),
+	})
+}
+
+// Sync ensures changes are committed.
+func (c *Client) Sync(_ string) error {
+	// records are appended on each change
+	return nil
+}
+
+// CompareByTitleOnly returns true if issues should be compared by title only
+// Some storages may not be able to fetch other details like URL in GetIssues.
+func (c *Client) CompareByTitleOnly() bool {
+	return true
+}
@@ -0,0 +1,83 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestExport(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Export")
+}
+
+type testIssue struct {
+	title string
+	url   string
+	repo  string
+}
+
+func (i testIssue) Title() string { return i.title }
+func (i testIssue) URL() string   { return i.url }
+func (i testIssue) Repo() string  { return i.repo }
+
+var _ = Describe("Client", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "todohub.ndjson")
+	})
+
+	newClient := func() *Client {
+		client, err := New(&Settings{Path: path}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		client.now = func() time.Time {
+			return time.Date(2024, 5, 1, 10, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+		}
+		return client
+	}
+
+	It("appends records", func() {
+		client := newClient()
+		Expect(client.CreateProject("To review")).To(Succeed())
+		Expect(client.CreateProject("To review")).To(Succeed())
+		Expect(client.Create("To review", testIssue{title: "Fix", url: "https://github.com/o/r/pull/1", repo: "o/r"})).To(Succeed())
+		Expect(client.Delete("To review", testIssue{title: "Fix", url: "https://github.com/o/r/pull/1", repo: "o/r"})).To(Succeed())
+
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(
+			`{"time":"2024-05-01T08:00:00Z","event":"list_created","list":"To review"}` + "\n" +
+				`{"time":"2024-05-01T08:00:00Z","event":"created","list":"To review","title":"Fix","url":"https://github.com/o/r/pull/1","repo":"o/r","key":"https://github.com/o/r/pull/1"}` + "\n" +
+				`{"time":"2024-05-01T08:00:00Z","event":"deleted","list":"To review","title":"Fix","url":"https://github.com/o/r/pull/1","repo":"o/r","key":"https://github.com/o/r/pull/1"}` + "\n",
+		))
+	})
+
+	It("replays lists on start", func() {
+		client := newClient()
+		Expect(client.Create("To review", testIssue{title: "Fix", url: "https://github.com/o/r/pull/1", repo: "o/r"})).To(Succeed())
+		Expect(client.Create("To review", testIssue{title: "Bump"})).To(Succeed())
+		Expect(client.Create("Assigned", testIssue{title: "Bug"})).To(Succeed())
+		Expect(client.Delete("To review", testIssue{title: "Bump"})).To(Succeed())
+
+		issues, err := newClient().GetIssues("To review")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(Equal([]issue.Issue{
+			Item{key: "https://github.com/o/r/pull/1", title: "Fix", url: "https://github.com/o/r/pull/1", repo: "o/r"},
+		}))
+		Expect(newClient().GetIssues("Missing")).To(BeEmpty())
+	})
+
+	It("reports invalid lines", func() {
+		Expect(os.WriteFile(path, []byte("{\"event\":\"created\"}\n\nnot json\n"), 0o600)).To(Succeed())
+		_, err := New(&Settings{Path: path}, logrus.New())
+		Expect(err).To(MatchError(ContainSubstring("todohub.ndjson:3")))
+	})
+})
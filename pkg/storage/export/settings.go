@@ -0,0 +1,16 @@
+package export
+
+// Settings holds info about export file.
+type Settings struct {
+	// Path is a newline-delimited JSON file, records are appended to it.
+	Path string `yaml:"path"`
+}
+
+// Implement storage.Settings.
+func (s Settings) ID() string {
+	return "export"
+}
+
+func (s Settings) Project() string {
+	return s.Path
+}
@@ -0,0 +1,160 @@
+package githubprojects
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const projectQuery = `query($owner: String!, $number: Int!, $field: String!) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectV2(number: $number) {
+        id
+        field(name: $field) {
+          ... on ProjectV2SingleSelectField {
+            id
+            options { id name }
+          }
+        }
+      }
+    }
+  }
+}`
+
+const itemsQuery = `query($project: ID!, $field: String!, $cursor: String) {
+  node(id: $project) {
+    ... on ProjectV2 {
+      items(first: 100, after: $cursor) {
+        nodes {
+          id
+          fieldValueByName(name: $field) {
+            ... on ProjectV2ItemFieldSingleSelectValue { name }
+          }
+          content {
+            __typename
+            ... on Issue { title url repository { nameWithOwner } }
+            ... on PullRequest { title url repository { nameWithOwner } }
+            ... on DraftIssue { title }
+          }
+        }
+        pageInfo { hasNextPage endCursor }
+      }
+    }
+  }
+}`
+
+const resourceQuery = `query($url: URI!) {
+  resource(url: $url) {
+    ... on Issue { id }
+    ... on PullRequest { id }
+  }
+}`
+
+const addItemMutation = `mutation($project: ID!, $content: ID!, $field: String!) {
+  addProjectV2ItemById(input: {projectId: $project, contentId: $content}) {
+    item {
+      id
+      isArchived
+      fieldValueByName(name: $field) {
+        ... on ProjectV2ItemFieldSingleSelectValue { name }
+      }
+    }
+  }
+}`
+
+const addDraftMutation = `mutation($project: ID!, $title: String!, $body: String) {
+  addProjectV2DraftIssue(input: {projectId: $project, title: $title, body: $body}) {
+    projectItem { id }
+  }
+}`
+
+const setStatusMutation = `mutation($project: ID!, $item: ID!, $field: ID!, $option: String!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field, value: {singleSelectOptionId: $option}}) {
+    projectV2Item { id }
+  }
+}`
+
+const clearStatusMutation = `mutation($project: ID!, $item: ID!, $field: ID!) {
+  clearProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field}) {
+    projectV2Item { id }
+  }
+}`
+
+const deleteItemMutation = `mutation($project: ID!, $item: ID!) {
+  deleteProjectV2Item(input: {projectId: $project, itemId: $item}) {
+    deletedItemId
+  }
+}`
+
+// option is a single-select field option.
+type option struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// projectItem is a project item with its status and content.
+type projectItem struct {
+	ID     string `json:"id"`
+	Status *struct {
+		Name string `json:"name"`
+	} `json:"fieldValueByName"` //nolint:tagliatelle
+	Content *struct {
+		Type       string `json:"__typename"` //nolint:tagliatelle
+		Title      string `json:"title"`
+		URL        string `json:"url"`
+		Repository struct {
+			NameWithOwner string `json:"nameWithOwner"` //nolint:tagliatelle
+		} `json:"repository"`
+	} `json:"content"`
+}
+
+// graphQLError is an error returned by GraphQL API.
+type graphQLError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// query sends GraphQL request and decodes response data into result.
+func (c *Client) query(q string, variables map[string]interface{}, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     q,
+		"variables": variables,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, c.settings.endpoint(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.settings.Token)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("github projects: %s", resp.Status)
+	}
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphQLError  `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return err
+	}
+	if len(response.Errors) > 0 {
+		errs := make([]error, len(response.Errors))
+		for i, e := range response.Errors {
+			errs[i] = fmt.Errorf("github projects: %s", e.Message)
+		}
+		return errors.Join(errs...)
+	}
+	return json.Unmarshal(response.Data, result)
+}
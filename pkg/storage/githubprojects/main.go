@@ -0,0 +1,364 @@
+package githubprojects
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/backoff"
+	"github.com/vrutkovs/todohub/pkg/issue"
+)
+
+// Client is a wrapper for GitHub Projects v2 GraphQL API.
+type Client struct {
+	http      *http.Client
+	settings  *Settings
+	projectID string
+	fieldID   string
+	mu        sync.Mutex
+	options   []option
+	// items are list items cached by GetIssues for the current sync
+	items  map[string][]Item
+	logger *logrus.Logger
+}
+
+// Item struct holds information about the project item.
+type Item struct {
+	id    string
+	draft bool
+	title string
+	url   string
+	repo  string
+}
+
+func (i Item) Title() string {
+	return i.title
+}
+
+func (i Item) URL() string {
+	return i.url
+}
+
+func (i Item) Repo() string {
+	return i.repo
+}
+
+// New returns GitHub project client.
+func New(s *Settings, logger *logrus.Logger) (*Client, error) {
+	c := &Client{
+		http:     backoff.NewClient(s.Retry.Policy()),
+		settings: s,
+		items:    make(map[string][]Item),
+		logger:   logger,
+	}
+	if err := c.loadProject(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// loadProject finds project and its status field options.
+func (c *Client) loadProject() error {
+	var result struct {
+		Owner *struct {
+			Project *struct {
+				ID    string `json:"id"`
+				Field *struct {
+					ID      string   `json:"id"`
+					Options []option `json:"options"`
+				} `json:"field"`
+			} `json:"projectV2"` //nolint:tagliatelle
+		} `json:"repositoryOwner"` //nolint:tagliatelle
+	}
+	err := c.query(projectQuery, map[string]interface{}{
+		"owner":  c.settings.Owner,
+		"number": c.settings.Number,
+		"field":  c.settings.statusField(),
+	}, &result)
+	if err != nil {
+		return err
+	}
+	if result.Owner == nil || result.Owner.Project == nil {
+		return fmt.Errorf("github projects: project %s/%d not found", c.settings.Owner, c.settings.Number)
+	}
+	project := result.Owner.Project
+	if project.Field == nil || project.Field.ID == "" {
+		return fmt.Errorf("github projects: single-select field %q not found", c.settings.statusField())
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.projectID = project.ID
+	c.fieldID = project.Field.ID
+	c.options = project.Field.Options
+	return nil
+}
+
+// optionID returns status option ID by list name.
+func (c *Client) optionID(name string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, o := range c.options {
+		if o.Name == name {
+			return o.ID, true
+		}
+	}
+	return "", false
+}
+
+// CreateProject checks that status field has an option for the list.
+// Options are not created by todohub: GitHub replaces all options on update,
+// which clears status of existing items, so missing options have to be added by hand.
+func (c *Client) CreateProject(name string) error {
+	if _, ok := c.optionID(name); ok {
+		return nil
+	}
+	// Option may have been added since the project was loaded
+	if err := c.loadProject(); err != nil {
+		return err
+	}
+	if _, ok := c.optionID(name); !ok {
+		return fmt.Errorf("github projects: option %q not found in %q field, add it in project settings", name, c.settings.statusField())
+	}
+	return nil
+}
+
+// fetchItems returns project items having list status.
+// Archived items are kept, so that they are not added again.
+func (c *Client) fetchItems(name string) ([]Item, error) {
+	results := make([]Item, 0)
+	var cursor *string
+	for {
+		var result struct {
+			Node struct {
+				Items struct {
+					Nodes    []projectItem `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"` //nolint:tagliatelle
+						EndCursor   string `json:"endCursor"`   //nolint:tagliatelle
+					} `json:"pageInfo"` //nolint:tagliatelle
+				} `json:"items"`
+			} `json:"node"`
+		}
+		err := c.query(itemsQuery, map[string]interface{}{
+			"project": c.projectID,
+			"field":   c.settings.statusField(),
+			"cursor":  cursor,
+		}, &result)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range result.Node.Items.Nodes {
+			if item.Status == nil || item.Status.Name != name || item.Content == nil {
+				continue
+			}
+			results = append(results, Item{
+				id:    item.ID,
+				draft: item.Content.Type == "DraftIssue",
+				title: item.Content.Title,
+				url:   item.Content.URL,
+				repo:  item.Content.Repository.NameWithOwner,
+			})
+		}
+		pageInfo := result.Node.Items.PageInfo
+		if !pageInfo.HasNextPage {
+			break
+		}
+		cursor = &pageInfo.EndCursor
+	}
+	return results, nil
+}
+
+func (c *Client) GetIssues(listName string) ([]issue.Issue, error) {
+	items, err := c.fetchItems(listName)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.items[listName] = items
+	c.mu.Unlock()
+	issues := make([]issue.Issue, len(items))
+	for i, item := range items {
+		issues[i] = item
+	}
+	return issues, nil
+}
+
+// contentID returns node ID of GitHub issue or pull request by URL.
+// Empty ID is returned for links outside of GitHub.
+func (c *Client) contentID(url string) (string, error) {
+	if url == "" {
+		return "", nil
+	}
+	var result struct {
+		Resource *struct {
+			ID string `json:"id"`
+		} `json:"resource"`
+	}
+	if err := c.query(resourceQuery, map[string]interface{}{"url": url}, &result); err != nil {
+		return "", fmt.Errorf("github projects: failed to resolve %s: %w", url, err)
+	}
+	if result.Resource == nil {
+		return "", nil
+	}
+	return result.Resource.ID, nil
+}
+
+// Create adds issue or PR to the project by node ID and sets its status.
+// Project has a single item per issue or PR, so an item which already has a status
+// is left in its list. Items from other sources are added as draft issues.
+func (c *Client) Create(listName string, item issue.Issue) error {
+	optionID, ok := c.optionID(listName)
+	if !ok {
+		return fmt.Errorf("github projects: no status option %q", listName)
+	}
+
+	contentID, err := c.contentID(item.URL())
+	if err != nil {
+		return err
+	}
+	var itemID string
+	if contentID != "" {
+		var result struct {
+			Add struct {
+				Item projectItem `json:"item"`
+			} `json:"addProjectV2ItemById"` //nolint:tagliatelle
+		}
+		err := c.query(addItemMutation, map[string]interface{}{
+			"project": c.projectID,
+			"content": contentID,
+			"field":   c.settings.statusField(),
+		}, &result)
+		if err != nil {
+			return err
+		}
+		if status := result.Add.Item.Status; status != nil {
+			c.logger.WithFields(logrus.Fields{
+				"storage": "github_projects", "list": listName, "url": item.URL(), "status": status.Name,
+			}).Info("item already has a status, skipping")
+			return nil
+		}
+		itemID = result.Add.Item.ID
+	} else {
+		var result struct {
+			Add struct {
+				Item struct {
+					ID string `json:"id"`
+				} `json:"projectItem"` //nolint:tagliatelle
+			} `json:"addProjectV2DraftIssue"` //nolint:tagliatelle
+		}
+		err := c.query(addDraftMutation, map[string]interface{}{
+			"project": c.projectID,
+			"title":   item.Title(),
+			"body":    draftBody(item),
+		}, &result)
+		if err != nil {
+			return err
+		}
+		itemID = result.Add.Item.ID
+	}
+
+	var result struct{}
+	err = c.query(setStatusMutation, map[string]interface{}{
+		"project": c.projectID,
+		"item":    itemID,
+		"field":   c.fieldID,
+		"option":  optionID,
+	}, &result)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if items, ok := c.items[listName]; ok {
+		c.items[listName] = append(items, Item{id: itemID, draft: contentID == "", title: item.Title(), url: item.URL(), repo: item.Repo()})
+	}
+	return nil
+}
+
+// draftBody keeps issue link and description in draft issue body.
+func draftBody(item issue.Issue) string {
+	parts := make([]string, 0, 2)
+	if item.URL() != "" {
+		parts = append(parts, item.URL())
+	}
+	if description := issue.Description(item); description != "" {
+		parts = append(parts, description)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// cachedItems returns list items fetched by GetIssues, the project is paged through
+// only if the list has not been fetched yet.
+func (c *Client) cachedItems(listName string) ([]Item, error) {
+	c.mu.Lock()
+	items, ok := c.items[listName]
+	c.mu.Unlock()
+	if ok {
+		return items, nil
+	}
+	items, err := c.fetchItems(listName)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.items[listName] = items
+	c.mu.Unlock()
+	return items, nil
+}
+
+// Delete clears status of issue or PR item, so that it stays in the project.
+// Draft issues are removed from the project.
+func (c *Client) Delete(listName string, item issue.Issue) error {
+	items, err := c.cachedItems(listName)
+	if err != nil {
+		return err
+	}
+	deleted := make(map[string]bool)
+	for _, i := range items {
+		if i.Title() != item.Title() {
+			continue
+		}
+		var result struct{}
+		if i.draft {
+			err = c.query(deleteItemMutation, map[string]interface{}{
+				"project": c.projectID,
+				"item":    i.id,
+			}, &result)
+		} else {
+			err = c.query(clearStatusMutation, map[string]interface{}{
+				"project": c.projectID,
+				"item":    i.id,
+				"field":   c.fieldID,
+			}, &result)
+		}
+		if err != nil {
+			return err
+		}
+		deleted[i.id] = true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	remaining := make([]Item, 0, len(c.items[listName]))
+	for _, i := range c.items[listName] {
+		if !deleted[i.id] {
+			remaining = append(remaining, i)
+		}
+	}
+	c.items[listName] = remaining
+	return nil
+}
+
+// Sync ensures changes are committed.
+func (c *Client) Sync(_ string) error {
+	// project changes are applied immediately
+	return nil
+}
+
+// CompareByTitleOnly returns true if issues should be compared by title only
+// Draft issues don't have URL and repo.
+func (c *Client) CompareByTitleOnly() bool {
+	return true
+}
@@ -0,0 +1,194 @@
+package githubprojects
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestGithubProjects(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GitHub Projects")
+}
+
+type testIssue struct {
+	title string
+	url   string
+}
+
+func (i testIssue) Title() string { return i.title }
+func (i testIssue) URL() string   { return i.url }
+func (i testIssue) Repo() string  { return "" }
+
+// fields are top-level query and mutation fields used by the client.
+var fields = []string{
+	"repositoryOwner", "resource", "addProjectV2ItemById", "addProjectV2DraftIssue",
+	"updateProjectV2ItemFieldValue", "clearProjectV2ItemFieldValue", "node", "deleteProjectV2Item",
+}
+
+var _ = Describe("Client", func() {
+	var (
+		srv       *httptest.Server
+		mutations []string
+		variables []map[string]interface{}
+		queries   map[string]int
+		options   string
+		added     string
+		client    *Client
+	)
+
+	respond := func(w http.ResponseWriter, data string) {
+		_, err := w.Write([]byte(`{"data":` + data + `}`))
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		mutations = nil
+		variables = nil
+		queries = make(map[string]int)
+		options = `[{"id":"O1","name":"Todo"},{"id":"O2","name":"To review"}]`
+		added = `{"id":"I1"}`
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("Authorization")).To(Equal("Bearer token"))
+			var req struct {
+				Query     string                 `json:"query"`
+				Variables map[string]interface{} `json:"variables"`
+			}
+			Expect(json.NewDecoder(r.Body).Decode(&req)).To(Succeed())
+			name := ""
+			for _, field := range fields {
+				if strings.Contains(req.Query, field+"(") {
+					name = field
+					break
+				}
+			}
+			queries[name]++
+			if strings.HasPrefix(req.Query, "mutation") {
+				mutations = append(mutations, name)
+				variables = append(variables, req.Variables)
+			}
+			switch name {
+			case "repositoryOwner":
+				respond(w, `{"repositoryOwner":{"projectV2":{"id":"P1","field":{"id":"F1","options":`+options+`}}}}`)
+			case "resource":
+				if strings.HasSuffix(req.Variables["url"].(string), "/broken") {
+					_, err := w.Write([]byte(`{"errors":[{"message":"rate limited"}]}`))
+					Expect(err).NotTo(HaveOccurred())
+					return
+				}
+				if strings.HasPrefix(req.Variables["url"].(string), "https://github.com/") {
+					respond(w, `{"resource":{"id":"PR1"}}`)
+					return
+				}
+				respond(w, `{"resource":null}`)
+			case "addProjectV2ItemById":
+				Expect(req.Variables["field"]).To(Equal("Status"))
+				respond(w, `{"addProjectV2ItemById":{"item":`+added+`}}`)
+			case "addProjectV2DraftIssue":
+				respond(w, `{"addProjectV2DraftIssue":{"projectItem":{"id":"I2"}}}`)
+			case "updateProjectV2ItemFieldValue":
+				respond(w, `{"updateProjectV2ItemFieldValue":{"projectV2Item":{"id":"I1"}}}`)
+			case "clearProjectV2ItemFieldValue":
+				respond(w, `{"clearProjectV2ItemFieldValue":{"projectV2Item":{"id":"I1"}}}`)
+			case "node":
+				respond(w, `{"node":{"items":{"nodes":[
+					{"id":"I1","fieldValueByName":{"name":"To review"},"content":{"__typename":"PullRequest","title":"Fix","url":"https://github.com/o/r/pull/1","repository":{"nameWithOwner":"o/r"}}},
+					{"id":"I3","fieldValueByName":{"name":"Todo"},"content":{"__typename":"DraftIssue","title":"Other"}},
+					{"id":"I4","isArchived":true,"fieldValueByName":{"name":"To review"},"content":{"__typename":"DraftIssue","title":"Archived"}}
+				],"pageInfo":{"hasNextPage":false}}}}`)
+			case "deleteProjectV2Item":
+				respond(w, `{"deleteProjectV2Item":{"deletedItemId":"I1"}}`)
+			default:
+				Fail("unexpected query " + name)
+			}
+		}))
+		var err error
+		client, err = New(&Settings{Token: "token", Owner: "o", Number: 1, Endpoint: srv.URL}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		srv.Close()
+	})
+
+	It("doesn't change status options", func() {
+		Expect(client.CreateProject("Todo")).To(Succeed())
+		Expect(client.CreateProject("Done")).To(MatchError(`github projects: option "Done" not found in "Status" field, add it in project settings`))
+
+		// Option added by hand is picked up
+		options = `[{"id":"O1","name":"Todo"},{"id":"O3","name":"Done"}]`
+		Expect(client.CreateProject("Done")).To(Succeed())
+		id, ok := client.optionID("Done")
+		Expect(ok).To(BeTrue())
+		Expect(id).To(Equal("O3"))
+		Expect(mutations).To(BeEmpty())
+	})
+
+	It("adds GitHub items by node ID and others as drafts", func() {
+		Expect(client.CreateProject("To review")).To(Succeed())
+		Expect(client.Create("To review", testIssue{title: "Fix", url: "https://github.com/o/r/pull/1"})).To(Succeed())
+		Expect(client.Create("To review", testIssue{title: "Bug", url: "https://issues.example.com/browse/BUG-1"})).To(Succeed())
+		Expect(mutations).To(Equal([]string{
+			"addProjectV2ItemById", "updateProjectV2ItemFieldValue",
+			"addProjectV2DraftIssue", "updateProjectV2ItemFieldValue",
+		}))
+		Expect(variables[0]["content"]).To(Equal("PR1"))
+		Expect(variables[1]["option"]).To(Equal("O2"))
+		Expect(variables[2]["body"]).To(Equal("https://issues.example.com/browse/BUG-1"))
+	})
+
+	It("keeps items which already have a status", func() {
+		added = `{"id":"I1","fieldValueByName":{"name":"Todo"}}`
+		Expect(client.Create("To review", testIssue{title: "Fix", url: "https://github.com/o/r/pull/1"})).To(Succeed())
+		Expect(mutations).To(Equal([]string{"addProjectV2ItemById"}))
+	})
+
+	It("lists items by status, including archived ones", func() {
+		issues, err := client.GetIssues("To review")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(ConsistOf(
+			issue.Issue(Item{id: "I1", title: "Fix", url: "https://github.com/o/r/pull/1", repo: "o/r"}),
+			issue.Issue(Item{id: "I4", draft: true, title: "Archived"}),
+		))
+	})
+
+	It("clears status of deleted items, keeping them in the project", func() {
+		Expect(client.Delete("To review", testIssue{title: "Fix"})).To(Succeed())
+		Expect(client.Delete("To review", testIssue{title: "Fix"})).To(Succeed())
+		Expect(mutations).To(Equal([]string{"clearProjectV2ItemFieldValue"}))
+		Expect(variables[0]["item"]).To(Equal("I1"))
+		Expect(variables[0]["field"]).To(Equal("F1"))
+		Expect(queries["node"]).To(Equal(1))
+	})
+
+	It("removes deleted draft issues", func() {
+		Expect(client.Delete("Todo", testIssue{title: "Other"})).To(Succeed())
+		Expect(mutations).To(Equal([]string{"deleteProjectV2Item"}))
+		Expect(variables[0]["item"]).To(Equal("I3"))
+	})
+
+	It("deletes items created in the same sync without fetching", func() {
+		_, err := client.GetIssues("Todo")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Create("Todo", testIssue{title: "Bug", url: "https://issues.example.com/browse/BUG-1"})).To(Succeed())
+		Expect(client.Delete("Todo", testIssue{title: "Bug"})).To(Succeed())
+		Expect(mutations).To(HaveLen(3))
+		Expect(mutations[2]).To(Equal("deleteProjectV2Item"))
+		Expect(variables[2]["item"]).To(Equal("I2"))
+		Expect(queries["node"]).To(Equal(1))
+	})
+
+	It("fails to add item if its URL can't be resolved", func() {
+		err := client.Create("Todo", testIssue{title: "Fix", url: "https://github.com/o/r/pull/broken"})
+		Expect(err).To(MatchError(ContainSubstring("rate limited")))
+		Expect(mutations).To(BeEmpty())
+	})
+})
@@ -0,0 +1,47 @@
+package githubprojects
+
+import "github.com/vrutkovs/todohub/pkg/backoff"
+
+// DefaultEndpoint is GitHub GraphQL API URL.
+const DefaultEndpoint = "https://api.github.com/graphql"
+
+// DefaultStatusField is a single-select field which holds list names.
+const DefaultStatusField = "Status"
+
+// Settings holds info about GitHub project connection.
+// Issue or PR is added to the project once, so it's kept in the first list it was added to.
+type Settings struct {
+	Token string `yaml:"token"`
+	// Owner is an organization or user login owning the project.
+	Owner string `yaml:"owner"`
+	// Number is a project number from its URL.
+	Number int `yaml:"number"`
+	// StatusField defaults to "Status".
+	StatusField string `yaml:"status_field,omitempty"`
+	// Endpoint is GraphQL API URL, set it for GitHub Enterprise.
+	Endpoint string            `yaml:"endpoint,omitempty"`
+	Retry    *backoff.Settings `yaml:"retry,omitempty"`
+}
+
+// Implement storage.Settings.
+func (s Settings) ID() string {
+	return "github_projects"
+}
+
+func (s Settings) Project() string {
+	return s.Owner
+}
+
+func (s Settings) endpoint() string {
+	if s.Endpoint == "" {
+		return DefaultEndpoint
+	}
+	return s.Endpoint
+}
+
+func (s Settings) statusField() string {
+	if s.StatusField == "" {
+		return DefaultStatusField
+	}
+	return s.StatusField
+}
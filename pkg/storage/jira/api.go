@@ -0,0 +1,162 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PageSize is a number of issues requested per search page.
+const PageSize = 100
+
+// searchFields are issue fields requested in searches.
+const searchFields = "summary,status"
+
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			Name           string `json:"name"`
+			StatusCategory struct {
+				Key string `json:"key"`
+			} `json:"statusCategory"` //nolint:tagliatelle
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+// done returns true if issue status belongs to Done category.
+func (i jiraIssue) done() bool {
+	return i.Fields.Status.StatusCategory.Key == statusCategoryDone
+}
+
+type remoteLink struct {
+	GlobalID string `json:"globalId,omitempty"` //nolint:tagliatelle
+	Object   struct {
+		URL   string `json:"url"`
+		Title string `json:"title"`
+	} `json:"object"`
+}
+
+type transition struct {
+	ID string `json:"id"`
+	To struct {
+		Name           string `json:"name"`
+		StatusCategory struct {
+			Key string `json:"key"`
+		} `json:"statusCategory"` //nolint:tagliatelle
+	} `json:"to"`
+}
+
+// search returns all issues matching JQL query.
+// Jira Cloud only supports enhanced search with page tokens.
+func (c *Client) search(jql string) ([]jiraIssue, error) {
+	if c.settings.Cloud {
+		return c.searchCloud(jql)
+	}
+	results := make([]jiraIssue, 0)
+	for {
+		params := url.Values{}
+		params.Set("jql", jql)
+		params.Set("fields", searchFields)
+		params.Set("startAt", strconv.Itoa(len(results)))
+		params.Set("maxResults", strconv.Itoa(PageSize))
+		var page struct {
+			Issues []jiraIssue `json:"issues"`
+			Total  int         `json:"total"`
+		}
+		if err := c.do(http.MethodGet, "rest/api/2/search", params, nil, &page); err != nil {
+			return nil, err
+		}
+		results = append(results, page.Issues...)
+		if len(page.Issues) == 0 || len(results) >= page.Total {
+			return results, nil
+		}
+	}
+}
+
+func (c *Client) searchCloud(jql string) ([]jiraIssue, error) {
+	results := make([]jiraIssue, 0)
+	nextPageToken := ""
+	for {
+		params := url.Values{}
+		params.Set("jql", jql)
+		params.Set("fields", searchFields)
+		params.Set("maxResults", strconv.Itoa(PageSize))
+		if nextPageToken != "" {
+			params.Set("nextPageToken", nextPageToken)
+		}
+		var page struct {
+			Issues        []jiraIssue `json:"issues"`
+			NextPageToken string      `json:"nextPageToken"` //nolint:tagliatelle
+			IsLast        bool        `json:"isLast"`        //nolint:tagliatelle
+		}
+		if err := c.do(http.MethodGet, "rest/api/3/search/jql", params, nil, &page); err != nil {
+			return nil, err
+		}
+		results = append(results, page.Issues...)
+		if page.IsLast || page.NextPageToken == "" {
+			return results, nil
+		}
+		nextPageToken = page.NextPageToken
+	}
+}
+
+// jqlQuote returns JQL string literal.
+func jqlQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// do sends JSON request to Jira REST API and decodes response into result.
+func (c *Client) do(method, path string, params url.Values, body, result interface{}) error {
+	u := c.endpoint.JoinPath(path)
+	if params != nil {
+		u.RawQuery = params.Encode()
+	}
+	var reqBody io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(context.Background(), method, u.String(), reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			ErrorMessages []string          `json:"errorMessages"` //nolint:tagliatelle
+			Errors        map[string]string `json:"errors"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil {
+			messages := apiErr.ErrorMessages
+			for field, message := range apiErr.Errors {
+				messages = append(messages, field+": "+message)
+			}
+			if len(messages) > 0 {
+				return fmt.Errorf("jira: %s %s: %s: %s", method, u.Path, resp.Status, strings.Join(messages, "; "))
+			}
+		}
+		return fmt.Errorf("jira: %s %s: %s", method, u.Path, resp.Status)
+	}
+	if result == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,265 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/backoff"
+	"github.com/vrutkovs/todohub/pkg/issue"
+)
+
+// statusCategoryDone is a status category key of resolved issues.
+const statusCategoryDone = "done"
+
+// Client creates Jira issues for synced items.
+// Issues are marked with a label, lists are either labels or workflow statuses.
+type Client struct {
+	http     *http.Client
+	endpoint *url.URL
+	settings *Settings
+	// statuses are loaded once in New, so that concurrent syncs only read them
+	statuses map[string]bool
+	logger   *logrus.Logger
+}
+
+// Item struct holds information about the issue.
+// Remote links are not fetched, since items are compared by title only.
+type Item struct {
+	key   string
+	title string
+}
+
+func (i Item) Title() string {
+	return i.title
+}
+
+func (i Item) URL() string {
+	return ""
+}
+
+func (i Item) Repo() string {
+	return ""
+}
+
+// New returns Jira storage client.
+func New(s *Settings, logger *logrus.Logger) (*Client, error) {
+	if s.ProjectKey == "" {
+		return nil, errors.New("jira: project is not set")
+	}
+	if s.listAs() != ListAsLabel && s.listAs() != ListAsStatus {
+		return nil, fmt.Errorf("jira: unknown list_as %q", s.ListAs)
+	}
+	httpClient, err := s.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Transport = &backoff.Transport{Base: httpClient.Transport, Policy: s.Retry.Policy()}
+	endpoint, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		http:     httpClient,
+		endpoint: endpoint,
+		settings: s,
+		logger:   logger,
+	}
+	// Check that project is accessible
+	if err := c.do(http.MethodGet, "rest/api/2/project/"+url.PathEscape(s.ProjectKey), nil, nil, nil); err != nil {
+		return nil, err
+	}
+	if s.listAs() == ListAsStatus {
+		if err := c.loadStatuses(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// loadStatuses reads workflow statuses of the issue type.
+func (c *Client) loadStatuses() error {
+	var issueTypes []struct {
+		Name     string `json:"name"`
+		Statuses []struct {
+			Name string `json:"name"`
+		} `json:"statuses"`
+	}
+	if err := c.do(http.MethodGet, "rest/api/2/project/"+url.PathEscape(c.settings.ProjectKey)+"/statuses", nil, nil, &issueTypes); err != nil {
+		return err
+	}
+	c.statuses = make(map[string]bool)
+	for _, t := range issueTypes {
+		if t.Name != c.settings.issueType() {
+			continue
+		}
+		for _, s := range t.Statuses {
+			c.statuses[s.Name] = true
+		}
+	}
+	return nil
+}
+
+// listLabel builds label from list name, labels can't have spaces.
+func listLabel(name string) string {
+	return strings.Join(strings.Fields(name), "_")
+}
+
+// listJQL returns query for managed issues in the list.
+func (c *Client) listJQL(listName string) string {
+	jql := fmt.Sprintf("project = %s AND labels = %s", jqlQuote(c.settings.ProjectKey), jqlQuote(c.settings.label()))
+	if c.settings.listAs() == ListAsStatus {
+		return jql + " AND status = " + jqlQuote(listName)
+	}
+	return jql + " AND labels = " + jqlQuote(listLabel(listName))
+}
+
+// CreateProject checks that workflow has a status for the list.
+// Labels don't need to be created.
+func (c *Client) CreateProject(name string) error {
+	if c.settings.listAs() != ListAsStatus {
+		return nil
+	}
+	if !c.statuses[name] {
+		return fmt.Errorf("jira: status %q not found in %s workflow", name, c.settings.issueType())
+	}
+	return nil
+}
+
+// GetIssues returns managed issues in the list.
+// In label mode resolved issues are kept, so that issues closed by hand are not recreated.
+func (c *Client) GetIssues(listName string) ([]issue.Issue, error) {
+	found, err := c.search(c.listJQL(listName))
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]issue.Issue, len(found))
+	for i, f := range found {
+		issues[i] = Item{
+			key:   f.Key,
+			title: f.Fields.Summary,
+		}
+	}
+	return issues, nil
+}
+
+func issuePath(key string, elem ...string) string {
+	return strings.Join(append([]string{"rest/api/2/issue", url.PathEscape(key)}, elem...), "/")
+}
+
+// Create adds an issue with source URL as remote link.
+func (c *Client) Create(listName string, item issue.Issue) error {
+	labels := []string{c.settings.label()}
+	if c.settings.listAs() == ListAsLabel {
+		labels = append(labels, listLabel(listName))
+	}
+	fields := map[string]interface{}{
+		"project":   map[string]string{"key": c.settings.ProjectKey},
+		"issuetype": map[string]string{"name": c.settings.issueType()},
+		"summary":   item.Title(),
+		"labels":    labels,
+	}
+	if description := issue.Description(item); description != "" {
+		fields["description"] = description
+	}
+	if c.settings.Component != "" {
+		fields["components"] = []map[string]string{{"name": c.settings.Component}}
+	}
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := c.do(http.MethodPost, "rest/api/2/issue", nil, map[string]interface{}{"fields": fields}, &created); err != nil {
+		return err
+	}
+	if item.URL() != "" {
+		link := remoteLink{GlobalID: item.URL()}
+		link.Object.URL = item.URL()
+		link.Object.Title = item.Title()
+		if err := c.do(http.MethodPost, issuePath(created.Key, "remotelink"), nil, link, nil); err != nil {
+			return err
+		}
+	}
+	if c.settings.listAs() != ListAsStatus {
+		return nil
+	}
+	// Jira has no transitions to the same status, so issue created in initial status stays as is
+	var current jiraIssue
+	if err := c.do(http.MethodGet, issuePath(created.Key), url.Values{"fields": {"status"}}, nil, &current); err != nil {
+		return err
+	}
+	if current.Fields.Status.Name == listName {
+		return nil
+	}
+	return c.transition(created.Key, func(t transition) bool {
+		return t.To.Name == listName
+	})
+}
+
+// Delete resolves issues with matching title.
+// In label mode list label is removed as well, so that resolved issue leaves the list.
+func (c *Client) Delete(listName string, item issue.Issue) error {
+	found, err := c.search(c.listJQL(listName))
+	if err != nil {
+		return err
+	}
+	for _, f := range found {
+		if f.Fields.Summary != item.Title() {
+			continue
+		}
+		if !f.done() {
+			err := c.transition(f.Key, func(t transition) bool {
+				return t.To.StatusCategory.Key == statusCategoryDone
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if c.settings.listAs() != ListAsLabel {
+			continue
+		}
+		update := map[string]interface{}{
+			"update": map[string]interface{}{
+				"labels": []map[string]string{{"remove": listLabel(listName)}},
+			},
+		}
+		if err := c.do(http.MethodPut, issuePath(f.Key), nil, update, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transition moves issue using first available transition accepted by match.
+func (c *Client) transition(key string, match func(transition) bool) error {
+	var available struct {
+		Transitions []transition `json:"transitions"`
+	}
+	if err := c.do(http.MethodGet, issuePath(key, "transitions"), nil, nil, &available); err != nil {
+		return err
+	}
+	for _, t := range available.Transitions {
+		if !match(t) {
+			continue
+		}
+		c.logger.WithFields(logrus.Fields{"storage": "jira", "issue": key, "status": t.To.Name}).Info("transitioning issue")
+		return c.do(http.MethodPost, issuePath(key, "transitions"), nil, map[string]interface{}{
+			"transition": map[string]string{"id": t.ID},
+		}, nil)
+	}
+	return fmt.Errorf("jira: no matching transition for %s", key)
+}
+
+// Sync ensures changes are committed.
+func (c *Client) Sync(_ string) error {
+	// jira changes are applied immediately
+	return nil
+}
+
+// CompareByTitleOnly returns true if issues should be compared by title only
+// Some storages may not be able to fetch other details like URL in GetIssues.
+func (c *Client) CompareByTitleOnly() bool {
+	return true
+}
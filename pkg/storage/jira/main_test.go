@@ -0,0 +1,349 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/backoff"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	jirasource "github.com/vrutkovs/todohub/pkg/source/jira"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestJira(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Jira storage")
+}
+
+type testIssue struct {
+	title       string
+	url         string
+	description string
+}
+
+func (i testIssue) Title() string       { return i.title }
+func (i testIssue) URL() string         { return i.url }
+func (i testIssue) Repo() string        { return "" }
+func (i testIssue) Description() string { return i.description }
+
+type fakeIssue struct {
+	key         string
+	summary     string
+	description string
+	components  []string
+	labels      []string
+	status      string
+	links       []string
+}
+
+var (
+	jqlLabelRegex  = regexp.MustCompile(`labels = "([^"]*)"`)
+	jqlStatusRegex = regexp.MustCompile(`status = "([^"]*)"`)
+	issuePathRegex = regexp.MustCompile(`^/rest/api/2/issue/([A-Z]+-\d+)(?:/(\w+))?$`)
+)
+
+// statusCategories maps fake workflow statuses to categories.
+var statusCategories = map[string]string{
+	"To Do":       "new",
+	"In Review":   "indeterminate",
+	"Done":        "done",
+	"Won't Do":    "done",
+	"In Progress": "indeterminate",
+}
+
+// fakeServer keeps a Jira project in memory.
+type fakeServer struct {
+	mu     sync.Mutex
+	issues []*fakeIssue
+	// rateLimited is a number of requests rejected with 429
+	rateLimited int
+}
+
+func (f *fakeServer) find(key string) *fakeIssue {
+	for _, i := range f.issues {
+		if i.key == key {
+			return i
+		}
+	}
+	return nil
+}
+
+// matches evaluates label and status conditions of JQL query.
+func (i *fakeIssue) matches(jql string) bool {
+	for _, m := range jqlLabelRegex.FindAllStringSubmatch(jql, -1) {
+		found := false
+		for _, l := range i.labels {
+			found = found || l == m[1]
+		}
+		if !found {
+			return false
+		}
+	}
+	if m := jqlStatusRegex.FindStringSubmatch(jql); m != nil && i.status != m[1] {
+		return false
+	}
+	return true
+}
+
+func (i *fakeIssue) toJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"key": i.key,
+		"fields": map[string]interface{}{
+			"summary": i.summary,
+			"status": map[string]interface{}{
+				"name":           i.status,
+				"statusCategory": map[string]string{"key": statusCategories[i.status]},
+			},
+		},
+	}
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.rateLimited > 0 {
+		f.rateLimited--
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var result interface{}
+	m := issuePathRegex.FindStringSubmatch(r.URL.Path)
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/project/TEAM":
+		result = map[string]string{"key": "TEAM"}
+	case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/project/TEAM/statuses":
+		result = []map[string]interface{}{
+			{"name": "Bug", "statuses": []map[string]string{{"name": "Triage"}}},
+			{"name": "Task", "statuses": []map[string]string{{"name": "To Do"}, {"name": "In Review"}, {"name": "Done"}}},
+		}
+	case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/search":
+		Expect(r.URL.Query().Get("jql")).To(HavePrefix(`project = "TEAM" AND labels = "todohub"`))
+		issues := make([]map[string]interface{}, 0)
+		for _, i := range f.issues {
+			if i.matches(r.URL.Query().Get("jql")) {
+				issues = append(issues, i.toJSON())
+			}
+		}
+		result = map[string]interface{}{"issues": issues, "total": len(issues)}
+	case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+		var body struct {
+			Fields struct {
+				Summary     string              `json:"summary"`
+				Description string              `json:"description"`
+				Labels      []string            `json:"labels"`
+				Components  []map[string]string `json:"components"`
+				IssueType   map[string]string   `json:"issuetype"`
+			} `json:"fields"`
+		}
+		Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+		Expect(body.Fields.IssueType).To(Equal(map[string]string{"name": "Task"}))
+		i := &fakeIssue{
+			key:         fmt.Sprintf("TEAM-%d", len(f.issues)+1),
+			summary:     body.Fields.Summary,
+			description: body.Fields.Description,
+			labels:      body.Fields.Labels,
+			status:      "To Do",
+		}
+		for _, c := range body.Fields.Components {
+			i.components = append(i.components, c["name"])
+		}
+		f.issues = append(f.issues, i)
+		w.WriteHeader(http.StatusCreated)
+		result = map[string]string{"key": i.key}
+	case m != nil && f.find(m[1]) == nil:
+		w.WriteHeader(http.StatusNotFound)
+		result = map[string]interface{}{"errorMessages": []string{"Issue does not exist or you do not have permission to see it."}}
+	case m != nil && m[2] == "remotelink" && r.Method == http.MethodPost:
+		var link remoteLink
+		Expect(json.NewDecoder(r.Body).Decode(&link)).To(Succeed())
+		i := f.find(m[1])
+		i.links = append(i.links, link.Object.URL)
+		w.WriteHeader(http.StatusCreated)
+		result = map[string]int{"id": len(i.links)}
+	case m != nil && m[2] == "" && r.Method == http.MethodGet:
+		Expect(r.URL.Query().Get("fields")).To(Equal("status"))
+		result = f.find(m[1]).toJSON()
+	case m != nil && m[2] == "transitions" && r.Method == http.MethodGet:
+		transitions := make([]map[string]interface{}, 0)
+		for _, name := range []string{"To Do", "In Review", "Done", "Won't Do"} {
+			// Jira offers no transitions to the current status
+			if name == f.find(m[1]).status {
+				continue
+			}
+			transitions = append(transitions, map[string]interface{}{
+				"id": name,
+				"to": map[string]interface{}{"name": name, "statusCategory": map[string]string{"key": statusCategories[name]}},
+			})
+		}
+		result = map[string]interface{}{"transitions": transitions}
+	case m != nil && m[2] == "transitions" && r.Method == http.MethodPost:
+		var body struct {
+			Transition map[string]string `json:"transition"`
+		}
+		Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+		f.find(m[1]).status = body.Transition["id"]
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case m != nil && m[2] == "" && r.Method == http.MethodPut:
+		var body struct {
+			Update struct {
+				Labels []map[string]string `json:"labels"`
+			} `json:"update"`
+		}
+		Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+		i := f.find(m[1])
+		for _, op := range body.Update.Labels {
+			labels := make([]string, 0)
+			for _, l := range i.labels {
+				if l != op["remove"] {
+					labels = append(labels, l)
+				}
+			}
+			i.labels = labels
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		result = map[string]interface{}{"errorMessages": []string{"Not found"}}
+	}
+	Expect(json.NewEncoder(w).Encode(result)).To(Succeed())
+}
+
+var _ = Describe("Client", func() {
+	var (
+		fake *fakeServer
+		srv  *httptest.Server
+	)
+
+	settings := func(listAs string) *Settings {
+		return &Settings{
+			Connection: jirasource.Connection{Endpoint: srv.URL, Token: "token"},
+			ProjectKey: "TEAM",
+			Component:  "Upstream",
+			ListAs:     listAs,
+		}
+	}
+
+	BeforeEach(func() {
+		fake = &fakeServer{}
+		srv = httptest.NewServer(fake)
+	})
+
+	AfterEach(func() {
+		srv.Close()
+	})
+
+	It("keeps lists in labels", func() {
+		client, err := New(settings(""), logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.CreateProject("To review")).To(Succeed())
+		Expect(client.Create("To review", testIssue{title: "Fix", url: "https://github.com/o/r/pull/1", description: "Failing checks:\n- ci"})).To(Succeed())
+		Expect(client.Create("To review", testIssue{title: "Bump", url: "https://github.com/o/r/pull/2"})).To(Succeed())
+		Expect(client.Create("Backlog", testIssue{title: "Other"})).To(Succeed())
+		Expect(*fake.issues[0]).To(Equal(fakeIssue{
+			key:         "TEAM-1",
+			summary:     "Fix",
+			description: "Failing checks:\n- ci",
+			components:  []string{"Upstream"},
+			labels:      []string{"todohub", "To_review"},
+			status:      "To Do",
+			links:       []string{"https://github.com/o/r/pull/1"},
+		}))
+
+		issues, err := client.GetIssues("To review")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(ConsistOf(
+			issue.Issue(Item{key: "TEAM-1", title: "Fix"}),
+			issue.Issue(Item{key: "TEAM-2", title: "Bump"}),
+		))
+
+		// Issue resolved by hand is kept in the list
+		fake.issues[1].status = "Won't Do"
+		Expect(client.GetIssues("To review")).To(HaveLen(2))
+
+		Expect(client.Delete("To review", testIssue{title: "Fix"})).To(Succeed())
+		Expect(client.Delete("To review", testIssue{title: "Bump"})).To(Succeed())
+		Expect(fake.issues[0].status).To(Equal("Done"))
+		Expect(fake.issues[0].labels).To(Equal([]string{"todohub"}))
+		Expect(fake.issues[1].status).To(Equal("Won't Do"))
+		Expect(client.GetIssues("To review")).To(BeEmpty())
+		Expect(client.GetIssues("Backlog")).To(HaveLen(1))
+	})
+
+	It("keeps lists in statuses", func() {
+		client, err := New(settings(ListAsStatus), logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.statuses).To(Equal(map[string]bool{"To Do": true, "In Review": true, "Done": true}))
+		Expect(client.CreateProject("In Review")).To(Succeed())
+		Expect(client.CreateProject("Triage")).To(MatchError(`jira: status "Triage" not found in Task workflow`))
+
+		Expect(client.Create("In Review", testIssue{title: "Fix", url: "https://github.com/o/r/pull/1"})).To(Succeed())
+		Expect(fake.issues[0].labels).To(Equal([]string{"todohub"}))
+		Expect(fake.issues[0].status).To(Equal("In Review"))
+		Expect(client.GetIssues("In Review")).To(HaveLen(1))
+
+		Expect(client.Delete("In Review", testIssue{title: "Fix"})).To(Succeed())
+		Expect(fake.issues[0].status).To(Equal("Done"))
+		Expect(client.GetIssues("In Review")).To(BeEmpty())
+	})
+
+	It("creates issues in initial status", func() {
+		client, err := New(settings(ListAsStatus), logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.CreateProject("To Do")).To(Succeed())
+		Expect(client.Create("To Do", testIssue{title: "Fix"})).To(Succeed())
+		Expect(fake.issues[0].status).To(Equal("To Do"))
+		Expect(client.GetIssues("To Do")).To(HaveLen(1))
+	})
+
+	It("retries rate limited requests", func() {
+		s := settings("")
+		s.Retry = &backoff.Settings{InitialIntervalSeconds: 0.01, MaxElapsedSeconds: 5}
+		client, err := New(s, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		fake.rateLimited = 2
+		Expect(client.Create("To review", testIssue{title: "Fix", url: "https://github.com/o/r/pull/1"})).To(Succeed())
+		Expect(fake.rateLimited).To(BeZero())
+		Expect(fake.issues).To(HaveLen(1))
+	})
+
+	It("validates settings", func() {
+		_, err := New(&Settings{Connection: jirasource.Connection{Endpoint: srv.URL, Token: "token"}}, logrus.New())
+		Expect(err).To(MatchError("jira: project is not set"))
+		_, err = New(settings("column"), logrus.New())
+		Expect(err).To(MatchError(`jira: unknown list_as "column"`))
+		s := settings("")
+		s.ProjectKey = "OTHER"
+		_, err = New(s, logrus.New())
+		Expect(err).To(MatchError(ContainSubstring("Not found")))
+	})
+
+	DescribeTable("jqlQuote",
+		func(value, expected string) {
+			Expect(jqlQuote(value)).To(Equal(expected))
+		},
+		Entry("plain", "To review", `"To review"`),
+		Entry("quotes", `say "hi"`, `"say \"hi\""`),
+		Entry("backslash", `a\b`, `"a\\b"`),
+	)
+
+	It("builds list labels", func() {
+		Expect(listLabel("  To   review ")).To(Equal("To_review"))
+		Expect(strings.Contains(listLabel("a\tb"), " ")).To(BeFalse())
+	})
+})
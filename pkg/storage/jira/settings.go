@@ -0,0 +1,67 @@
+package jira
+
+import (
+	"github.com/vrutkovs/todohub/pkg/backoff"
+	jirasource "github.com/vrutkovs/todohub/pkg/source/jira"
+)
+
+const (
+	// ListAsLabel keeps list name in issue labels.
+	ListAsLabel = "label"
+	// ListAsStatus uses workflow status named after the list.
+	ListAsStatus = "status"
+)
+
+const (
+	// DefaultIssueType is used for created issues.
+	DefaultIssueType = "Task"
+	// DefaultLabel marks issues managed by todohub.
+	DefaultLabel = "todohub"
+)
+
+// Settings holds info about Jira project used as a storage.
+type Settings struct {
+	// Connection is shared with jira source.
+	jirasource.Connection `yaml:",inline"`
+	// ProjectKey is a project where issues are created, e.g. "TEAM".
+	ProjectKey string `yaml:"project"`
+	// IssueType defaults to "Task".
+	IssueType string `yaml:"issue_type,omitempty"`
+	// Component is added to created issues if set.
+	Component string `yaml:"component,omitempty"`
+	// ListAs is either "label" (default) or "status".
+	ListAs string `yaml:"list_as,omitempty"`
+	// Label marks issues managed by todohub, defaults to "todohub".
+	Label string            `yaml:"label,omitempty"`
+	Retry *backoff.Settings `yaml:"retry,omitempty"`
+}
+
+// Implement storage.Settings.
+func (s Settings) ID() string {
+	return "jira"
+}
+
+func (s Settings) Project() string {
+	return s.ProjectKey
+}
+
+func (s Settings) issueType() string {
+	if s.IssueType == "" {
+		return DefaultIssueType
+	}
+	return s.IssueType
+}
+
+func (s Settings) label() string {
+	if s.Label == "" {
+		return DefaultLabel
+	}
+	return s.Label
+}
+
+func (s Settings) listAs() string {
+	if s.ListAs == "" {
+		return ListAsLabel
+	}
+	return s.ListAs
+}
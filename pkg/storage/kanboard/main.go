@@ -0,0 +1,240 @@
+package kanboard
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/backoff"
+	"github.com/vrutkovs/todohub/pkg/issue"
+)
+
+// statusActive selects open tasks.
+const statusActive = 1
+
+// Client is a wrapper for Kanboard JSON-RPC API.
+// Lists are columns of the project, items are tasks with external links.
+type Client struct {
+	http      *http.Client
+	settings  *Settings
+	projectID int
+	requestID int64
+	logger    *logrus.Logger
+}
+
+// Task struct holds information about the task.
+type Task struct {
+	id    int
+	title string
+	url   string
+}
+
+func (t Task) Title() string {
+	return t.title
+}
+
+// URL returns first external link of the task.
+func (t Task) URL() string {
+	return t.url
+}
+
+func (t Task) Repo() string {
+	return ""
+}
+
+type project struct {
+	ID rpcInt `json:"id"`
+}
+
+type column struct {
+	ID    rpcInt `json:"id"`
+	Title string `json:"title"`
+}
+
+type task struct {
+	ID       rpcInt `json:"id"`
+	Title    string `json:"title"`
+	ColumnID rpcInt `json:"column_id"`
+}
+
+type externalLink struct {
+	URL string `json:"url"`
+}
+
+// New returns Kanboard client.
+func New(s *Settings, logger *logrus.Logger) (*Client, error) {
+	if s.ProjectName == "" {
+		return nil, errors.New("kanboard: project is not set")
+	}
+	c := &Client{
+		http:     backoff.NewClient(s.Retry.Policy()),
+		settings: s,
+		logger:   logger,
+	}
+	if err := c.ensureProjectExists(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ensureProjectExists finds project by name, creating it if its missing.
+func (c *Client) ensureProjectExists() error {
+	var p *project
+	if err := c.call("getProjectByName", map[string]interface{}{"name": c.settings.ProjectName}, &p); err != nil {
+		return err
+	}
+	if p != nil {
+		c.projectID = int(p.ID)
+		return nil
+	}
+	c.logger.WithFields(logrus.Fields{"storage": "kanboard", "project": c.settings.ProjectName}).Info("creating project")
+	var id rpcInt
+	if err := c.call("createProject", map[string]interface{}{"name": c.settings.ProjectName}, &id); err != nil {
+		return err
+	}
+	c.projectID = int(id)
+	return nil
+}
+
+// findColumn returns column ID by list name, zero if its missing.
+func (c *Client) findColumn(name string) (int, error) {
+	var columns []column
+	if err := c.call("getColumns", map[string]interface{}{"project_id": c.projectID}, &columns); err != nil {
+		return 0, err
+	}
+	for _, col := range columns {
+		if col.Title == name {
+			return int(col.ID), nil
+		}
+	}
+	return 0, nil
+}
+
+// ensureColumnExists returns column ID for the list, creating it if its missing.
+func (c *Client) ensureColumnExists(name string) (int, error) {
+	id, err := c.findColumn(name)
+	if err != nil || id != 0 {
+		return id, err
+	}
+	c.logger.WithFields(logrus.Fields{"storage": "kanboard", "list": name}).Info("creating column")
+	var created rpcInt
+	err = c.call("addColumn", map[string]interface{}{
+		"project_id": c.projectID,
+		"title":      name,
+	}, &created)
+	return int(created), err
+}
+
+func (c *Client) CreateProject(name string) error {
+	_, err := c.ensureColumnExists(name)
+	return err
+}
+
+// fetchTasks returns open tasks in the column.
+func (c *Client) fetchTasks(columnID int) ([]task, error) {
+	var tasks []task
+	err := c.call("getAllTasks", map[string]interface{}{
+		"project_id": c.projectID,
+		"status_id":  statusActive,
+	}, &tasks)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]task, 0)
+	for _, t := range tasks {
+		if int(t.ColumnID) == columnID {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) GetIssues(listName string) ([]issue.Issue, error) {
+	issues := make([]issue.Issue, 0)
+	columnID, err := c.findColumn(listName)
+	if err != nil || columnID == 0 {
+		return issues, err
+	}
+	tasks, err := c.fetchTasks(columnID)
+	if err != nil {
+		return issues, err
+	}
+	for _, t := range tasks {
+		var links []externalLink
+		if err := c.call("getAllExternalTaskLinks", map[string]interface{}{"task_id": int(t.ID)}, &links); err != nil {
+			return issues, err
+		}
+		item := Task{
+			id:    int(t.ID),
+			title: t.Title,
+		}
+		if len(links) > 0 {
+			item.url = links[0].URL
+		}
+		issues = append(issues, item)
+	}
+	return issues, nil
+}
+
+// Create adds a task with repo tag and attaches issue URL as external link.
+func (c *Client) Create(listName string, item issue.Issue) error {
+	columnID, err := c.ensureColumnExists(listName)
+	if err != nil {
+		return err
+	}
+	params := map[string]interface{}{
+		"project_id":  c.projectID,
+		"column_id":   columnID,
+		"title":       item.Title(),
+		"description": issue.Description(item),
+	}
+	if item.Repo() != "" {
+		params["tags"] = []string{item.Repo()}
+	}
+	var taskID rpcInt
+	if err := c.call("createTask", params, &taskID); err != nil {
+		return err
+	}
+	if item.URL() == "" {
+		return nil
+	}
+	return c.call("createExternalTaskLink", map[string]interface{}{
+		"task_id":    int(taskID),
+		"url":        item.URL(),
+		"dependency": "related",
+		"type":       "weblink",
+	}, nil)
+}
+
+// Delete closes open tasks with matching title.
+func (c *Client) Delete(listName string, item issue.Issue) error {
+	columnID, err := c.findColumn(listName)
+	if err != nil || columnID == 0 {
+		return err
+	}
+	tasks, err := c.fetchTasks(columnID)
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		if t.Title != item.Title() {
+			continue
+		}
+		if err := c.call("closeTask", map[string]interface{}{"task_id": int(t.ID)}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sync ensures changes are committed.
+func (c *Client) Sync(_ string) error {
+	// kanboard changes are applied immediately
+	return nil
+}
+
+// CompareByTitleOnly returns true if issues should be compared by title only
+// Some storages may not be able to fetch other details like URL in GetIssues.
+func (c *Client) CompareByTitleOnly() bool {
+	return true
+}
@@ -0,0 +1,231 @@
+package kanboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestKanboard(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Kanboard")
+}
+
+type testIssue struct {
+	title string
+	url   string
+	repo  string
+}
+
+func (i testIssue) Title() string { return i.title }
+func (i testIssue) URL() string   { return i.url }
+func (i testIssue) Repo() string  { return i.repo }
+
+type fakeTask struct {
+	id       int
+	title    string
+	columnID int
+	tags     []string
+	active   bool
+	links    []string
+}
+
+// fakeServer keeps a Kanboard project in memory.
+// Like Kanboard, it returns IDs as strings in listings.
+type fakeServer struct {
+	mu       sync.Mutex
+	nextID   int
+	projects map[string]int
+	columns  map[int]string
+	tasks    []*fakeTask
+}
+
+func (f *fakeServer) id() int {
+	f.nextID++
+	return f.nextID
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if user, token, _ := r.BasicAuth(); user != DefaultUsername || token != "token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var req struct {
+		Method string                 `json:"method"`
+		ID     int                    `json:"id"`
+		Params map[string]interface{} `json:"params"`
+	}
+	Expect(json.NewDecoder(r.Body).Decode(&req)).To(Succeed())
+	param := func(name string) int {
+		return int(req.Params[name].(float64))
+	}
+	var result interface{}
+	switch req.Method {
+	case "getProjectByName":
+		if id, ok := f.projects[req.Params["name"].(string)]; ok {
+			result = map[string]string{"id": strconv.Itoa(id)}
+		}
+	case "createProject":
+		id := f.id()
+		f.projects[req.Params["name"].(string)] = id
+		result = id
+	case "getColumns":
+		columns := make([]map[string]string, 0)
+		for id, title := range f.columns {
+			columns = append(columns, map[string]string{"id": strconv.Itoa(id), "title": title})
+		}
+		result = columns
+	case "addColumn":
+		id := f.id()
+		f.columns[id] = req.Params["title"].(string)
+		result = id
+	case "getAllTasks":
+		tasks := make([]map[string]string, 0)
+		for _, t := range f.tasks {
+			if t.active {
+				tasks = append(tasks, map[string]string{"id": strconv.Itoa(t.id), "title": t.title, "column_id": strconv.Itoa(t.columnID)})
+			}
+		}
+		result = tasks
+	case "createTask":
+		if req.Params["title"] == "" {
+			result = false
+			break
+		}
+		t := &fakeTask{id: f.id(), title: req.Params["title"].(string), columnID: param("column_id"), active: true}
+		if tags, ok := req.Params["tags"].([]interface{}); ok {
+			for _, tag := range tags {
+				t.tags = append(t.tags, tag.(string))
+			}
+		}
+		f.tasks = append(f.tasks, t)
+		result = t.id
+	case "createExternalTaskLink":
+		for _, t := range f.tasks {
+			if t.id == param("task_id") {
+				t.links = append(t.links, req.Params["url"].(string))
+			}
+		}
+		result = f.id()
+	case "getAllExternalTaskLinks":
+		links := make([]map[string]string, 0)
+		for _, t := range f.tasks {
+			if t.id == param("task_id") {
+				for _, l := range t.links {
+					links = append(links, map[string]string{"url": l})
+				}
+			}
+		}
+		result = links
+	case "closeTask":
+		for _, t := range f.tasks {
+			if t.id == param("task_id") {
+				t.active = false
+			}
+		}
+		result = true
+	default:
+		Expect(json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"error":   map[string]interface{}{"code": -32601, "message": "Method not found"},
+		})).To(Succeed())
+		return
+	}
+	Expect(json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      req.ID,
+		"result":  result,
+	})).To(Succeed())
+}
+
+var _ = Describe("Client", func() {
+	var (
+		fake *fakeServer
+		srv  *httptest.Server
+	)
+
+	BeforeEach(func() {
+		fake = &fakeServer{
+			nextID:   100,
+			projects: map[string]int{"Review": 1},
+			columns:  map[int]string{2: "Backlog"},
+		}
+		srv = httptest.NewServer(fake)
+	})
+
+	AfterEach(func() {
+		srv.Close()
+	})
+
+	It("finds or creates project", func() {
+		client, err := New(&Settings{Endpoint: srv.URL, Token: "token", ProjectName: "Review"}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.projectID).To(Equal(1))
+
+		client, err = New(&Settings{Endpoint: srv.URL, Token: "token", ProjectName: "Upstream"}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.projectID).To(Equal(101))
+	})
+
+	It("creates columns once", func() {
+		client, err := New(&Settings{Endpoint: srv.URL, Token: "token", ProjectName: "Review"}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.CreateProject("To review")).To(Succeed())
+		Expect(client.CreateProject("To review")).To(Succeed())
+		Expect(client.CreateProject("Backlog")).To(Succeed())
+		Expect(fake.columns).To(Equal(map[int]string{2: "Backlog", 101: "To review"}))
+	})
+
+	It("creates, lists and closes tasks", func() {
+		client, err := New(&Settings{Endpoint: srv.URL, Token: "token", ProjectName: "Review"}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Create("Backlog", testIssue{title: "Fix", url: "https://github.com/o/r/pull/1", repo: "o/r"})).To(Succeed())
+		Expect(client.Create("Backlog", testIssue{title: "Bump", url: "https://github.com/o/r/pull/2"})).To(Succeed())
+		Expect(client.Create("Other", testIssue{title: "Elsewhere"})).To(Succeed())
+		Expect(fake.tasks[0].tags).To(Equal([]string{"o/r"}))
+
+		issues, err := client.GetIssues("Backlog")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(ConsistOf(
+			issue.Issue(Task{id: 101, title: "Fix", url: "https://github.com/o/r/pull/1"}),
+			issue.Issue(Task{id: 103, title: "Bump", url: "https://github.com/o/r/pull/2"}),
+		))
+
+		Expect(client.Delete("Backlog", testIssue{title: "Fix"})).To(Succeed())
+		Expect(fake.tasks[0].active).To(BeFalse())
+		issues, err = client.GetIssues("Backlog")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0].Title()).To(Equal("Bump"))
+	})
+
+	It("returns no issues for missing column", func() {
+		client, err := New(&Settings{Endpoint: srv.URL, Token: "token", ProjectName: "Review"}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		issues, err := client.GetIssues("Missing")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(BeEmpty())
+	})
+
+	It("reports failed calls", func() {
+		client, err := New(&Settings{Endpoint: srv.URL, Token: "token", ProjectName: "Review"}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Create("Backlog", testIssue{})).To(MatchError("kanboard: createTask failed"))
+		Expect(client.call("removeEverything", nil, nil)).To(MatchError("kanboard: removeEverything: Method not found (-32601)"))
+
+		_, err = New(&Settings{Endpoint: srv.URL, Token: "wrong", ProjectName: "Review"}, logrus.New())
+		Expect(err).To(MatchError(ContainSubstring("401")))
+	})
+})
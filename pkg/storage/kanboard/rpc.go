@@ -0,0 +1,97 @@
+package kanboard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+)
+
+// rpcInt decodes IDs, which Kanboard returns either as numbers or strings.
+type rpcInt int
+
+func (i *rpcInt) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		*i = rpcInt(n)
+		return nil
+	}
+	var n int
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*i = rpcInt(n)
+	return nil
+}
+
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	ID      int64       `json:"id"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	ID     int64           `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// call sends JSON-RPC request and decodes result.
+func (c *Client) call(method string, params, result interface{}) error {
+	data, err := json.Marshal(rpcRequest{
+		JSONRPC: "2.0",
+		Method:  method,
+		ID:      atomic.AddInt64(&c.requestID, 1),
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, c.settings.Endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	username := c.settings.Username
+	if username == "" {
+		username = DefaultUsername
+	}
+	req.SetBasicAuth(username, c.settings.Token)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("kanboard: %s: %s", method, resp.Status)
+	}
+	var r rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return fmt.Errorf("kanboard: %s: %w", method, err)
+	}
+	if r.Error != nil {
+		return fmt.Errorf("kanboard: %s: %s (%d)", method, r.Error.Message, r.Error.Code)
+	}
+	// Kanboard reports failures of write methods as false result
+	if string(r.Result) == "false" {
+		return fmt.Errorf("kanboard: %s failed", method)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(r.Result, result)
+}
@@ -0,0 +1,27 @@
+package kanboard
+
+import "github.com/vrutkovs/todohub/pkg/backoff"
+
+// DefaultUsername is used with application API token.
+const DefaultUsername = "jsonrpc"
+
+// Settings holds info about Kanboard connection.
+type Settings struct {
+	// Endpoint is JSON-RPC URL, e.g. https://kanboard.example.com/jsonrpc.php
+	Endpoint string `yaml:"endpoint"`
+	// Username is "jsonrpc" for application token or a user name for personal token.
+	Username string `yaml:"username,omitempty"`
+	Token    string `yaml:"token"`
+	// ProjectName is a project holding list columns, created if missing.
+	ProjectName string            `yaml:"project"`
+	Retry       *backoff.Settings `yaml:"retry,omitempty"`
+}
+
+// Implement storage.Settings.
+func (s Settings) ID() string {
+	return "kanboard"
+}
+
+func (s Settings) Project() string {
+	return s.ProjectName
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+)
+
+// Mirror forwards changes applied to primary storage to mirror storages.
+// Lists are read from primary only, mirror failures are logged and don't fail the sync.
+type Mirror struct {
+	primary Client
+	mirrors []Client
+	logger  *logrus.Logger
+}
+
+// NewMirror returns storage writing to primary and all mirrors.
+func NewMirror(primary Client, logger *logrus.Logger, mirrors ...Client) *Mirror {
+	return &Mirror{
+		primary: primary,
+		mirrors: mirrors,
+		logger:  logger,
+	}
+}
+
+// forward applies the change to mirrors once primary has succeeded.
+func (m *Mirror) forward(op, list string, f func(Client) error) {
+	for _, mirror := range m.mirrors {
+		if err := f(mirror); err != nil {
+			m.logger.WithFields(logrus.Fields{"storage": "mirror", "op": op, "list": list}).WithError(err).Warn("mirror failed")
+		}
+	}
+}
+
+func (m *Mirror) CompareByTitleOnly() bool {
+	return m.primary.CompareByTitleOnly()
+}
+
+func (m *Mirror) CreateProject(name string) error {
+	if err := m.primary.CreateProject(name); err != nil {
+		return err
+	}
+	m.forward("create_project", name, func(c Client) error {
+		return c.CreateProject(name)
+	})
+	return nil
+}
+
+func (m *Mirror) GetIssues(name string) ([]issue.Issue, error) {
+	return m.primary.GetIssues(name)
+}
+
+func (m *Mirror) Create(name string, i issue.Issue) error {
+	if err := m.primary.Create(name, i); err != nil {
+		return err
+	}
+	m.forward("create", name, func(c Client) error {
+		return c.Create(name, i)
+	})
+	return nil
+}
+
+func (m *Mirror) Delete(name string, i issue.Issue) error {
+	if err := m.primary.Delete(name, i); err != nil {
+		return err
+	}
+	m.forward("delete", name, func(c Client) error {
+		return c.Delete(name, i)
+	})
+	return nil
+}
+
+func (m *Mirror) Sync(name string) error {
+	if err := m.primary.Sync(name); err != nil {
+		return err
+	}
+	m.forward("sync", name, func(c Client) error {
+		return c.Sync(name)
+	})
+	return nil
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+	"github.com/vrutkovs/todohub/pkg/storage/storagetest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestStorage(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Storage")
+}
+
+type testIssue struct {
+	title string
+}
+
+func (i testIssue) Title() string { return i.title }
+func (i testIssue) URL() string   { return "" }
+func (i testIssue) Repo() string  { return "" }
+
+// failingStorage fails all changes.
+type failingStorage struct {
+	storagetest.Storage
+}
+
+func (s *failingStorage) Create(string, issue.Issue) error {
+	return errors.New("create failed")
+}
+
+var _ = Describe("Mirror", func() {
+	It("forwards changes to mirrors", func() {
+		primary := storagetest.New(nil)
+		mirror := storagetest.New(nil)
+		m := NewMirror(primary, logrus.New(), mirror)
+		Expect(m.CreateProject("To review")).To(Succeed())
+		Expect(m.Create("To review", testIssue{title: "Fix"})).To(Succeed())
+		Expect(m.Create("To review", testIssue{title: "Bump"})).To(Succeed())
+		Expect(m.Delete("To review", testIssue{title: "Fix"})).To(Succeed())
+		Expect(m.Sync("To review")).To(Succeed())
+		Expect(mirror.Lists).To(Equal(primary.Lists))
+
+		// Lists are read from primary only
+		mirror.Lists["To review"] = nil
+		Expect(m.GetIssues("To review")).To(Equal([]issue.Issue{testIssue{title: "Bump"}}))
+	})
+
+	It("ignores mirror failures", func() {
+		mirror := &failingStorage{Storage: *storagetest.New(nil)}
+		m := NewMirror(storagetest.New(nil), logrus.New(), mirror)
+		Expect(m.Create("To review", testIssue{title: "Fix"})).To(Succeed())
+	})
+
+	It("doesn't forward primary failures", func() {
+		mirror := storagetest.New(nil)
+		m := NewMirror(&failingStorage{Storage: *storagetest.New(nil)}, logrus.New(), mirror)
+		Expect(m.Create("To review", testIssue{title: "Fix"})).To(MatchError("create failed"))
+		Expect(mirror.Lists).To(BeEmpty())
+	})
+})
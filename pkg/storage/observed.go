@@ -0,0 +1,30 @@
+package storage
+
+import "github.com/vrutkovs/todohub/pkg/issue"
+
+// ListObserver receives required items of every synced list,
+// e.g. to render them outside of storage.
+type ListObserver interface {
+	ListSynced(sourceID, list string, items []issue.Issue)
+}
+
+// Observed is a storage which passes synced lists to observers.
+type Observed struct {
+	Client
+	observers []ListObserver
+}
+
+// NewObserved returns storage notifying observers about synced lists.
+func NewObserved(c Client, observers ...ListObserver) *Observed {
+	return &Observed{
+		Client:    c,
+		observers: observers,
+	}
+}
+
+// ListSynced implements ListObserver.
+func (o *Observed) ListSynced(sourceID, list string, items []issue.Issue) {
+	for _, observer := range o.observers {
+		observer.ListSynced(sourceID, list, items)
+	}
+}
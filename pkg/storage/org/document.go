@@ -0,0 +1,220 @@
+package org
+
+import (
+	"regexp"
+	"strings"
+)
+
+// KeyProperty holds source key in entry PROPERTIES drawer.
+const KeyProperty = "TODOHUB_KEY"
+
+// archiveTag marks entries removed from source which were already done.
+const archiveTag = "ARCHIVE"
+
+// keywords are recognized TODO keywords, done ones are true.
+var keywords = map[string]bool{
+	"TODO":      false,
+	"NEXT":      false,
+	"WAITING":   false,
+	"HOLD":      false,
+	"DONE":      true,
+	"CANCELLED": true,
+	"CANCELED":  true,
+}
+
+var (
+	headingRegex = regexp.MustCompile(`^(\*+)\s+(.*?)\s*$`)
+	tagsRegex    = regexp.MustCompile(`\s+(:[\w@#%:]+:)$`)
+	linkRegex    = regexp.MustCompile(`^\[\[(?P<link>.+?)\]\[(?P<title>.*)\]\]$`)
+	propRegex    = regexp.MustCompile(`^\s*:([\w-]+):\s*(.*?)\s*$`)
+	nonTagChars  = regexp.MustCompile(`[^\w@#%]+`)
+)
+
+// document is an org file split into level 1 sections.
+// Lines which todohub doesn't manage are kept as is.
+type document struct {
+	preamble []string
+	sections []*section
+}
+
+// section is a level 1 heading holding a list.
+type section struct {
+	heading string
+	name    string
+	body    []string
+	entries []*entry
+}
+
+// entry is a level 2 heading with its contents.
+type entry struct {
+	lines []string
+}
+
+// parse splits file contents into sections and entries.
+func parse(data string) *document {
+	doc := &document{}
+	if data == "" {
+		return doc
+	}
+	lines := strings.Split(strings.TrimSuffix(data, "\n"), "\n")
+	var current *section
+	var currentEntry *entry
+	for _, line := range lines {
+		m := headingRegex.FindStringSubmatch(line)
+		switch {
+		case m != nil && len(m[1]) == 1:
+			current = &section{heading: line, name: m[2]}
+			currentEntry = nil
+			doc.sections = append(doc.sections, current)
+		case m != nil && len(m[1]) == 2 && current != nil:
+			currentEntry = &entry{lines: []string{line}}
+			current.entries = append(current.entries, currentEntry)
+		case currentEntry != nil:
+			currentEntry.lines = append(currentEntry.lines, line)
+		case current != nil:
+			current.body = append(current.body, line)
+		default:
+			doc.preamble = append(doc.preamble, line)
+		}
+	}
+	return doc
+}
+
+// String renders document back to org syntax.
+func (d *document) String() string {
+	lines := make([]string, 0)
+	lines = append(lines, d.preamble...)
+	for _, s := range d.sections {
+		lines = append(lines, s.heading)
+		lines = append(lines, s.body...)
+		for _, e := range s.entries {
+			lines = append(lines, e.lines...)
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// section returns section by list name.
+func (d *document) section(name string) *section {
+	for _, s := range d.sections {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// headline returns heading text without stars.
+func (e *entry) headline() string {
+	return strings.TrimSpace(strings.TrimPrefix(e.lines[0], "**"))
+}
+
+// keyword returns TODO keyword of the entry.
+func (e *entry) keyword() string {
+	word, _, _ := strings.Cut(e.headline(), " ")
+	if _, ok := keywords[word]; ok {
+		return word
+	}
+	return ""
+}
+
+// done returns true if entry state was changed to a done keyword.
+func (e *entry) done() bool {
+	return keywords[e.keyword()]
+}
+
+// text returns headline without keyword and tags.
+func (e *entry) text() string {
+	text := strings.TrimSpace(strings.TrimPrefix(e.headline(), e.keyword()))
+	return tagsRegex.ReplaceAllString(text, "")
+}
+
+func (e *entry) tags() []string {
+	m := tagsRegex.FindStringSubmatch(e.headline())
+	if m == nil {
+		return nil
+	}
+	return strings.Split(strings.Trim(m[1], ":"), ":")
+}
+
+func (e *entry) hasTag(tag string) bool {
+	for _, t := range e.tags() {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// addTag appends tag to the headline.
+func (e *entry) addTag(tag string) {
+	if e.hasTag(tag) {
+		return
+	}
+	tags := append(e.tags(), tag)
+	headline := tagsRegex.ReplaceAllString(e.headline(), "")
+	e.lines[0] = "** " + headline + " :" + strings.Join(tags, ":") + ":"
+}
+
+// link returns link target and title from entry text.
+// Plain text entries have no link.
+func (e *entry) link() (string, string) {
+	text := e.text()
+	m := linkRegex.FindStringSubmatch(text)
+	if m == nil {
+		return "", text
+	}
+	return m[1], m[2]
+}
+
+// property returns value from PROPERTIES drawer.
+func (e *entry) property(name string) string {
+	inDrawer := false
+	for _, line := range e.lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.EqualFold(trimmed, ":PROPERTIES:"):
+			inDrawer = true
+		case strings.EqualFold(trimmed, ":END:"):
+			inDrawer = false
+		case inDrawer:
+			if m := propRegex.FindStringSubmatch(line); m != nil && strings.EqualFold(m[1], name) {
+				return m[2]
+			}
+		}
+	}
+	return ""
+}
+
+// tag builds org tag from repo name, tags can't have slashes.
+func tag(repo string) string {
+	return strings.Trim(nonTagChars.ReplaceAllString(repo, "_"), "_")
+}
+
+// newEntry renders a TODO entry with link, repo tag and key property.
+func newEntry(title, link, repo, key, description string) *entry {
+	headline := "** TODO " + title
+	if link != "" {
+		headline = "** TODO [[" + link + "][" + title + "]]"
+	}
+	if t := tag(repo); t != "" {
+		headline += " :" + t + ":"
+	}
+	lines := []string{headline}
+	if key != "" {
+		lines = append(lines,
+			"   :PROPERTIES:",
+			"   :"+KeyProperty+": "+key,
+			"   :END:",
+		)
+	}
+	if description != "" {
+		for _, line := range strings.Split(description, "\n") {
+			lines = append(lines, "   "+line)
+		}
+	}
+	return &entry{lines: lines}
+}
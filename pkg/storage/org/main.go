@@ -0,0 +1,181 @@
+package org
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/fsutil"
+	"github.com/vrutkovs/todohub/pkg/issue"
+)
+
+// Client reads and writes org-mode file.
+type Client struct {
+	settings *Settings
+	// mu serializes file updates of concurrent source syncs
+	mu     sync.Mutex
+	logger *logrus.Logger
+}
+
+// Item struct holds information about the entry.
+type Item struct {
+	key   string
+	title string
+	url   string
+	repo  string
+}
+
+func (i Item) Title() string {
+	return i.title
+}
+
+func (i Item) URL() string {
+	return i.url
+}
+
+// Repo returns first entry tag.
+func (i Item) Repo() string {
+	return i.repo
+}
+
+// Key returns source key from PROPERTIES drawer.
+func (i Item) Key() string {
+	return i.key
+}
+
+// New returns org-mode client.
+func New(s *Settings, logger *logrus.Logger) (*Client, error) {
+	if s.Path == "" {
+		return nil, errors.New("org: path is not set")
+	}
+	return &Client{
+		settings: s,
+		logger:   logger,
+	}, nil
+}
+
+// load reads and parses the file, missing file is empty.
+func (c *Client) load() (*document, error) {
+	data, err := os.ReadFile(c.settings.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return parse(""), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parse(string(data)), nil
+}
+
+// save rewrites the file atomically keeping its mode.
+func (c *Client) save(doc *document) error {
+	mode := fs.FileMode(0o644)
+	if info, err := os.Stat(c.settings.Path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	return fsutil.WriteFile(c.settings.Path, []byte(doc.String()), mode)
+}
+
+// CreateProject adds a level 1 heading for the list if its missing.
+func (c *Client) CreateProject(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	doc, err := c.load()
+	if err != nil {
+		return err
+	}
+	if doc.section(name) != nil {
+		return nil
+	}
+	doc.sections = append(doc.sections, &section{heading: "* " + name, name: name})
+	return c.save(doc)
+}
+
+// GetIssues returns list entries including done ones,
+// so that entries closed by hand are not recreated.
+func (c *Client) GetIssues(listName string) ([]issue.Issue, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	doc, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]issue.Issue, 0)
+	s := doc.section(listName)
+	if s == nil {
+		return issues, nil
+	}
+	for _, e := range s.entries {
+		if e.hasTag(archiveTag) {
+			continue
+		}
+		link, title := e.link()
+		item := Item{
+			key:   e.property(KeyProperty),
+			title: title,
+			url:   link,
+		}
+		if tags := e.tags(); len(tags) > 0 {
+			item.repo = tags[0]
+		}
+		issues = append(issues, item)
+	}
+	return issues, nil
+}
+
+func (c *Client) Create(listName string, item issue.Issue) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	doc, err := c.load()
+	if err != nil {
+		return err
+	}
+	s := doc.section(listName)
+	if s == nil {
+		s = &section{heading: "* " + listName, name: listName}
+		doc.sections = append(doc.sections, s)
+	}
+	s.entries = append(s.entries, newEntry(item.Title(), item.URL(), item.Repo(), issue.Key(item), issue.Description(item)))
+	return c.save(doc)
+}
+
+// Delete removes TODO entries, done entries are archived instead.
+// Entries are matched by source key if known, by title otherwise.
+func (c *Client) Delete(listName string, item issue.Issue) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	doc, err := c.load()
+	if err != nil {
+		return err
+	}
+	s := doc.section(listName)
+	if s == nil {
+		return nil
+	}
+	entries := make([]*entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		if _, title := e.link(); !issue.Matches(item, e.property(KeyProperty), title) || e.hasTag(archiveTag) {
+			entries = append(entries, e)
+			continue
+		}
+		if e.done() {
+			e.addTag(archiveTag)
+			entries = append(entries, e)
+		}
+	}
+	s.entries = entries
+	return c.save(doc)
+}
+
+// Sync ensures changes are committed.
+func (c *Client) Sync(_ string) error {
+	// file is rewritten on each change
+	return nil
+}
+
+// CompareByTitleOnly returns true if issues should be compared by title only
+// Repo names are stored as tags, which can't hold all characters.
+func (c *Client) CompareByTitleOnly() bool {
+	return true
+}
@@ -0,0 +1,156 @@
+package org
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOrg(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Org")
+}
+
+type testIssue struct {
+	title string
+	url   string
+	repo  string
+}
+
+func (i testIssue) Title() string { return i.title }
+func (i testIssue) URL() string   { return i.url }
+func (i testIssue) Repo() string  { return i.repo }
+
+const sample = `#+TITLE: Work
+Some notes
+
+* Notes
+Not managed by todohub
+** Ideas
+* To review
+** TODO [[https://github.com/o/r/pull/1][Fix [flaky] test]] :o_r:
+   :PROPERTIES:
+   :TODOHUB_KEY: https://github.com/o/r/pull/1
+   :END:
+*** My notes
+** DONE [[https://github.com/o/r/pull/2][Bump deps]] :o_r:
+   CLOSED: [2024-01-02 Tue 10:00]
+** Plain entry
+`
+
+var _ = DescribeTable("tag",
+	func(repo, expected string) {
+		Expect(tag(repo)).To(Equal(expected))
+	},
+	Entry("Repo", "vrutkovs/todohub", "vrutkovs_todohub"),
+	Entry("Jira project", "OCPBUGS", "OCPBUGS"),
+	Entry("Spaces", " Release notes ", "Release_notes"),
+)
+
+var _ = Describe("Client", func() {
+	var (
+		path   string
+		client *Client
+	)
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "todohub.org")
+		Expect(os.WriteFile(path, []byte(sample), 0o600)).To(Succeed())
+		var err error
+		client, err = New(&Settings{Path: path}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	read := func() string {
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		return string(data)
+	}
+
+	It("keeps entries created concurrently in different lists", func() {
+		lists := []string{"To review", "Todo", "Blocked", "Later"}
+		var wg sync.WaitGroup
+		for _, list := range lists {
+			wg.Add(1)
+			go func(list string) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				for n := range 5 {
+					Expect(client.Create(list, testIssue{title: fmt.Sprintf("%s %d", list, n)})).To(Succeed())
+				}
+			}(list)
+		}
+		wg.Wait()
+
+		for _, list := range lists {
+			issues, err := client.GetIssues(list)
+			Expect(err).NotTo(HaveOccurred())
+			titles := make([]string, 0, len(issues))
+			for _, i := range issues {
+				if strings.HasPrefix(i.Title(), list+" ") {
+					titles = append(titles, i.Title())
+				}
+			}
+			Expect(titles).To(HaveLen(5), list)
+		}
+	})
+
+	It("keeps file unchanged on round trip", func() {
+		Expect(parse(sample).String()).To(Equal(sample))
+		Expect(client.CreateProject("To review")).To(Succeed())
+		Expect(read()).To(Equal(sample))
+	})
+
+	It("lists entries including done ones", func() {
+		issues, err := client.GetIssues("To review")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(Equal([]issue.Issue{
+			Item{key: "https://github.com/o/r/pull/1", title: "Fix [flaky] test", url: "https://github.com/o/r/pull/1", repo: "o_r"},
+			Item{title: "Bump deps", url: "https://github.com/o/r/pull/2", repo: "o_r"},
+			Item{title: "Plain entry"},
+		}))
+	})
+
+	It("creates lists and entries", func() {
+		Expect(client.CreateProject("Bugs")).To(Succeed())
+		Expect(client.Create("Bugs", testIssue{title: "Crash", url: "https://bugzilla.example.com/show_bug.cgi?id=1", repo: "OpenShift"})).To(Succeed())
+		Expect(read()).To(HaveSuffix(`* Bugs
+** TODO [[https://bugzilla.example.com/show_bug.cgi?id=1][Crash]] :OpenShift:
+   :PROPERTIES:
+   :TODOHUB_KEY: https://bugzilla.example.com/show_bug.cgi?id=1
+   :END:
+`))
+		issues, err := client.GetIssues("Bugs")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(1))
+		Expect(issue.Key(issues[0])).To(Equal("https://bugzilla.example.com/show_bug.cgi?id=1"))
+	})
+
+	It("removes TODO entries and archives done ones", func() {
+		Expect(client.Delete("To review", testIssue{title: "Fix [flaky] test"})).To(Succeed())
+		Expect(client.Delete("To review", testIssue{title: "Bump deps"})).To(Succeed())
+		Expect(read()).To(HaveSuffix(`* To review
+** DONE [[https://github.com/o/r/pull/2][Bump deps]] :o_r:ARCHIVE:
+   CLOSED: [2024-01-02 Tue 10:00]
+** Plain entry
+`))
+		issues, err := client.GetIssues("To review")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(Equal([]issue.Issue{Item{title: "Plain entry"}}))
+	})
+
+	It("creates missing file", func() {
+		Expect(os.Remove(path)).To(Succeed())
+		Expect(client.Create("Inbox", testIssue{title: "Read docs"})).To(Succeed())
+		Expect(read()).To(Equal("* Inbox\n** TODO Read docs\n"))
+	})
+})
@@ -0,0 +1,16 @@
+package org
+
+// Settings holds info about org-mode file.
+type Settings struct {
+	// Path is an .org file, created if missing.
+	Path string `yaml:"path"`
+}
+
+// Implement storage.Settings.
+func (s Settings) ID() string {
+	return "org"
+}
+
+func (s Settings) Project() string {
+	return s.Path
+}
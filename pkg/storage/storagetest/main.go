@@ -0,0 +1,58 @@
+// Package storagetest provides in-memory storage for source tests.
+package storagetest
+
+import "github.com/vrutkovs/todohub/pkg/issue"
+
+// Storage keeps cards in memory and implements storage.Client.
+type Storage struct {
+	Lists map[string][]issue.Issue
+}
+
+// New returns storage with the given lists.
+func New(lists map[string][]issue.Issue) *Storage {
+	if lists == nil {
+		lists = make(map[string][]issue.Issue)
+	}
+	return &Storage{Lists: lists}
+}
+
+func (s *Storage) CompareByTitleOnly() bool {
+	return true
+}
+
+func (s *Storage) CreateProject(name string) error {
+	if _, ok := s.Lists[name]; !ok {
+		s.Lists[name] = []issue.Issue{}
+	}
+	return nil
+}
+
+func (s *Storage) GetIssues(name string) ([]issue.Issue, error) {
+	return s.Lists[name], nil
+}
+
+func (s *Storage) Create(name string, i issue.Issue) error {
+	s.Lists[name] = append(s.Lists[name], i)
+	return nil
+}
+
+// Delete removes cards matching by key if known, by title otherwise.
+func (s *Storage) Delete(name string, i issue.Issue) error {
+	cards := make([]issue.Issue, 0, len(s.Lists[name]))
+	for _, card := range s.Lists[name] {
+		key := ""
+		if k, ok := card.(issue.Keyed); ok {
+			key = k.Key()
+		}
+		if issue.Matches(i, key, card.Title()) {
+			continue
+		}
+		cards = append(cards, card)
+	}
+	s.Lists[name] = cards
+	return nil
+}
+
+func (s *Storage) Sync(_ string) error {
+	return nil
+}
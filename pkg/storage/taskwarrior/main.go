@@ -0,0 +1,289 @@
+package taskwarrior
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+)
+
+// timeFormat is taskwarrior date format in JSON.
+const timeFormat = "20060102T150405Z"
+
+const (
+	statusPending   = "pending"
+	statusCompleted = "completed"
+	statusDeleted   = "deleted"
+)
+
+// Client runs taskwarrior commands.
+type Client struct {
+	settings *Settings
+	logger   *logrus.Logger
+}
+
+// task is a taskwarrior task in export format.
+// Unknown attributes are kept to survive reimport.
+type task map[string]interface{}
+
+func (t task) str(key string) string {
+	s, _ := t[key].(string)
+	return s
+}
+
+func (t task) tags() []string {
+	raw, _ := t["tags"].([]interface{})
+	tags := make([]string, 0, len(raw))
+	for _, tag := range raw {
+		if s, ok := tag.(string); ok {
+			tags = append(tags, s)
+		}
+	}
+	return tags
+}
+
+// Item struct holds information about the task.
+type Item struct {
+	uuid  string
+	title string
+	url   string
+}
+
+func (i Item) Title() string {
+	return i.title
+}
+
+func (i Item) URL() string {
+	return i.url
+}
+
+func (i Item) Repo() string {
+	return ""
+}
+
+// New returns taskwarrior client.
+func New(s *Settings, logger *logrus.Logger) (*Client, error) {
+	switch s.ListAs {
+	case "", ListProject, ListTag:
+	default:
+		return nil, fmt.Errorf("taskwarrior: list_as must be %q or %q", ListProject, ListTag)
+	}
+	if _, err := exec.LookPath(s.binary()); err != nil {
+		return nil, fmt.Errorf("taskwarrior: %w", err)
+	}
+	return &Client{
+		settings: s,
+		logger:   logger,
+	}, nil
+}
+
+// args returns rc overrides followed by the command.
+// URL UDA is defined here, so that no taskrc changes are required.
+func (c *Client) args(command ...string) []string {
+	uda := "rc.uda." + c.settings.urlAttribute()
+	args := []string{
+		"rc.confirmation=off",
+		"rc.verbose=nothing",
+		"rc.json.array=on",
+		uda + ".type=string",
+		uda + ".label=URL",
+	}
+	if c.settings.DataDir != "" {
+		args = append(args, "rc.data.location="+c.settings.DataDir)
+	}
+	return append(args, command...)
+}
+
+// run executes task with stdin and returns its output.
+func (c *Client) run(stdin []byte, command ...string) ([]byte, error) {
+	cmd := exec.Command(c.settings.binary(), c.args(command...)...) //nolint:gosec
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("taskwarrior: %s: %w: %s", strings.Join(command, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// export returns all tasks.
+func (c *Client) export() ([]task, error) {
+	out, err := c.run(nil, "export")
+	if err != nil {
+		return nil, err
+	}
+	var tasks []task
+	if len(bytes.TrimSpace(out)) == 0 {
+		return tasks, nil
+	}
+	if err := json.Unmarshal(out, &tasks); err != nil {
+		return nil, fmt.Errorf("taskwarrior: failed to parse export: %w", err)
+	}
+	return tasks, nil
+}
+
+// importTasks creates or updates tasks by UUID.
+func (c *Client) importTasks(tasks ...task) error {
+	data, err := json.Marshal(tasks)
+	if err != nil {
+		return err
+	}
+	_, err = c.run(data, "import")
+	return err
+}
+
+// project returns project name for the list.
+func (c *Client) project(listName string) string {
+	if c.settings.ParentProject == "" {
+		return listName
+	}
+	return c.settings.ParentProject + "." + listName
+}
+
+// tag returns tag name for the list, tags can't have spaces.
+func tag(listName string) string {
+	return strings.Join(strings.Fields(listName), "_")
+}
+
+// inList returns true if task belongs to the list.
+func (c *Client) inList(t task, listName string) bool {
+	if c.settings.ListAs == ListTag {
+		for _, tt := range t.tags() {
+			if tt == tag(listName) {
+				return true
+			}
+		}
+		return false
+	}
+	return t.str("project") == c.project(listName)
+}
+
+// listTasks returns pending and completed tasks in the list.
+// Completed tasks are kept so that sources don't recreate them.
+func (c *Client) listTasks(listName string) ([]task, error) {
+	tasks, err := c.export()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]task, 0)
+	for _, t := range tasks {
+		status := t.str("status")
+		if status != statusPending && status != statusCompleted {
+			continue
+		}
+		if c.inList(t, listName) {
+			results = append(results, t)
+		}
+	}
+	return results, nil
+}
+
+func (c *Client) CreateProject(_ string) error {
+	// projects and tags are created with tasks
+	return nil
+}
+
+func (c *Client) GetIssues(listName string) ([]issue.Issue, error) {
+	tasks, err := c.listTasks(listName)
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]issue.Issue, len(tasks))
+	for i, t := range tasks {
+		issues[i] = Item{
+			uuid:  t.str("uuid"),
+			title: t.str("description"),
+			url:   t.str(c.settings.urlAttribute()),
+		}
+	}
+	return issues, nil
+}
+
+func (c *Client) Create(listName string, item issue.Issue) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC().Format(timeFormat)
+	t := task{
+		"uuid":        id.String(),
+		"status":      statusPending,
+		"entry":       now,
+		"description": item.Title(),
+	}
+	if item.URL() != "" {
+		t[c.settings.urlAttribute()] = item.URL()
+	}
+	if c.settings.ListAs == ListTag {
+		t["tags"] = []string{tag(listName)}
+	} else {
+		t["project"] = c.project(listName)
+	}
+	if description := issue.Description(item); description != "" {
+		t["annotations"] = []map[string]string{{"entry": now, "description": description}}
+	}
+	return c.importTasks(t)
+}
+
+// Delete removes pending tasks, completed tasks are detached from the list.
+func (c *Client) Delete(listName string, item issue.Issue) error {
+	tasks, err := c.listTasks(listName)
+	if err != nil {
+		return err
+	}
+	updated := make([]task, 0)
+	for _, t := range tasks {
+		if t.str("description") != item.Title() {
+			continue
+		}
+		if t.str("status") == statusPending {
+			t["status"] = statusDeleted
+			t["end"] = time.Now().UTC().Format(timeFormat)
+		} else {
+			c.detach(t, listName)
+		}
+		updated = append(updated, t)
+	}
+	if len(updated) == 0 {
+		return nil
+	}
+	return c.importTasks(updated...)
+}
+
+// detach removes task from the list keeping its history.
+func (c *Client) detach(t task, listName string) {
+	if c.settings.ListAs != ListTag {
+		delete(t, "project")
+		return
+	}
+	tags := make([]string, 0)
+	for _, tt := range t.tags() {
+		if tt != tag(listName) {
+			tags = append(tags, tt)
+		}
+	}
+	if len(tags) == 0 {
+		delete(t, "tags")
+		return
+	}
+	t["tags"] = tags
+}
+
+// Sync ensures changes are committed.
+func (c *Client) Sync(_ string) error {
+	// tasks are written on import
+	return nil
+}
+
+// CompareByTitleOnly returns true if issues should be compared by title only
+// Repo is not stored in tasks.
+func (c *Client) CompareByTitleOnly() bool {
+	return true
+}
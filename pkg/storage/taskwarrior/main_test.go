@@ -0,0 +1,165 @@
+package taskwarrior
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestTaskwarrior(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Taskwarrior")
+}
+
+// Fake task binary from testdata is put on PATH, so that tests don't need taskwarrior.
+var _ = BeforeSuite(func() {
+	dir := GinkgoT().TempDir()
+	cmd := exec.Command("go", "build", "-o", filepath.Join(dir, DefaultBinary), "./testdata/task")
+	cmd.Stderr = GinkgoWriter
+	Expect(cmd.Run()).To(Succeed())
+	DeferCleanup(os.Setenv, "PATH", os.Getenv("PATH"))
+	Expect(os.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))).To(Succeed())
+})
+
+type testIssue struct {
+	title string
+	url   string
+}
+
+func (i testIssue) Title() string { return i.title }
+func (i testIssue) URL() string   { return i.url }
+func (i testIssue) Repo() string  { return "" }
+
+var _ = DescribeTable("inList",
+	func(s Settings, t task, expected bool) {
+		c := &Client{settings: &s}
+		Expect(c.inList(t, "To review")).To(Equal(expected))
+	},
+	Entry("Project", Settings{}, task{"project": "To review"}, true),
+	Entry("Parent project", Settings{ParentProject: "todohub"}, task{"project": "todohub.To review"}, true),
+	Entry("Other project", Settings{ParentProject: "todohub"}, task{"project": "To review"}, false),
+	Entry("Tag", Settings{ListAs: ListTag}, task{"tags": []interface{}{"work", "To_review"}}, true),
+	Entry("No tags", Settings{ListAs: ListTag}, task{"project": "To review"}, false),
+)
+
+var _ = DescribeTable("detach",
+	func(s Settings, t, expected task) {
+		c := &Client{settings: &s}
+		c.detach(t, "To review")
+		Expect(t).To(Equal(expected))
+	},
+	Entry("Project", Settings{}, task{"project": "To review", "description": "a"}, task{"description": "a"}),
+	Entry("Tags", Settings{ListAs: ListTag}, task{"tags": []interface{}{"work", "To_review"}}, task{"tags": []string{"work"}}),
+	Entry("Last tag", Settings{ListAs: ListTag}, task{"tags": []interface{}{"To_review"}}, task{}),
+)
+
+var _ = Describe("Client", func() {
+	var (
+		dir    string
+		client *Client
+	)
+
+	// calls returns arguments of task invocations.
+	calls := func() [][]string {
+		data, err := os.ReadFile(filepath.Join(dir, "calls.log"))
+		Expect(err).NotTo(HaveOccurred())
+		results := make([][]string, 0)
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			results = append(results, strings.Split(line, "\x00"))
+		}
+		return results
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		GinkgoT().Setenv("FAKE_TASK_LOG", filepath.Join(dir, "calls.log"))
+		GinkgoT().Setenv("TASKDATA", filepath.Join(dir, "data"))
+		Expect(os.Mkdir(filepath.Join(dir, "data"), 0o700)).To(Succeed())
+
+		var err error
+		client, err = New(&Settings{ParentProject: "todohub"}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("fails if task binary is missing", func() {
+		_, err := New(&Settings{Binary: filepath.Join(dir, "missing")}, logrus.New())
+		Expect(err).To(MatchError(ContainSubstring("taskwarrior:")))
+	})
+
+	It("creates, lists and deletes tasks", func() {
+		Expect(client.Create("To review", testIssue{title: "Fix", url: "https://github.com/o/r/pull/1"})).To(Succeed())
+		Expect(client.Create("Other", testIssue{title: "Other"})).To(Succeed())
+
+		issues, err := client.GetIssues("To review")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0].Title()).To(Equal("Fix"))
+		Expect(issues[0].URL()).To(Equal("https://github.com/o/r/pull/1"))
+
+		Expect(client.Delete("To review", issues[0])).To(Succeed())
+		Expect(client.GetIssues("To review")).To(BeEmpty())
+
+		all, err := client.export()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(all).To(HaveLen(2))
+		Expect(all[0].str("project")).To(Equal("todohub.To review"))
+		Expect(all[0].str("status")).To(Equal(statusDeleted))
+		Expect(all[0].str("end")).NotTo(BeEmpty())
+		Expect(all[1].str("project")).To(Equal("todohub.Other"))
+		Expect(all[1].str("status")).To(Equal(statusPending))
+	})
+
+	It("keeps completed tasks in the list", func() {
+		Expect(client.Create("To review", testIssue{title: "Fix"})).To(Succeed())
+		tasks, err := client.listTasks("To review")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tasks).To(HaveLen(1))
+		tasks[0]["status"] = statusCompleted
+		Expect(client.importTasks(tasks[0])).To(Succeed())
+
+		Expect(client.GetIssues("To review")).To(ConsistOf(issue.Issue(Item{uuid: tasks[0].str("uuid"), title: "Fix"})))
+
+		Expect(client.Delete("To review", testIssue{title: "Fix"})).To(Succeed())
+		Expect(client.GetIssues("To review")).To(BeEmpty())
+		all, err := client.export()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(all).To(HaveLen(1))
+		Expect(all[0].str("status")).To(Equal(statusCompleted))
+		Expect(all[0]).NotTo(HaveKey("project"))
+	})
+
+	It("stores lists as tags and URL in custom UDA", func() {
+		dataDir := filepath.Join(dir, "custom")
+		Expect(os.Mkdir(dataDir, 0o700)).To(Succeed())
+		client, err := New(&Settings{ListAs: ListTag, URLAttribute: "link", DataDir: dataDir}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Create("To review", testIssue{title: "Fix", url: "https://github.com/o/r/pull/1"})).To(Succeed())
+
+		Expect(filepath.Join(dataDir, "tasks.json")).To(BeAnExistingFile())
+		all, err := client.export()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(all).To(HaveLen(1))
+		Expect(all[0].tags()).To(Equal([]string{"To_review"}))
+		Expect(all[0].str("link")).To(Equal("https://github.com/o/r/pull/1"))
+
+		Expect(calls()).To(ContainElement(Equal([]string{
+			"rc.confirmation=off", "rc.verbose=nothing", "rc.json.array=on",
+			"rc.uda.link.type=string", "rc.uda.link.label=URL",
+			"rc.data.location=" + dataDir, "import",
+		})))
+	})
+
+	It("returns task errors", func() {
+		GinkgoT().Setenv("TASKDATA", "")
+		_, err := client.GetIssues("To review")
+		Expect(err).To(MatchError(ContainSubstring("taskwarrior: export: exit status 1: data location is not set")))
+	})
+})
@@ -0,0 +1,51 @@
+package taskwarrior
+
+// DefaultBinary is a taskwarrior executable name.
+const DefaultBinary = "task"
+
+// DefaultURLAttribute is a UDA holding issue URL.
+const DefaultURLAttribute = "todohuburl"
+
+const (
+	// ListProject maps lists to projects.
+	ListProject = "project"
+	// ListTag maps lists to tags.
+	ListTag = "tag"
+)
+
+// Settings holds info about taskwarrior setup.
+type Settings struct {
+	// Binary is a path to task executable, defaults to "task".
+	Binary string `yaml:"binary,omitempty"`
+	// DataDir overrides data.location, TASKDATA and TASKRC are respected otherwise.
+	DataDir string `yaml:"data_dir,omitempty"`
+	// ListAs is either "project" (default) or "tag".
+	ListAs string `yaml:"list_as,omitempty"`
+	// ParentProject is a parent project for lists, e.g. "todohub" makes "todohub.To review".
+	ParentProject string `yaml:"parent_project,omitempty"`
+	// URLAttribute is a string UDA name used to store issue URL.
+	URLAttribute string `yaml:"url_attribute,omitempty"`
+}
+
+// Implement storage.Settings.
+func (s Settings) ID() string {
+	return "taskwarrior"
+}
+
+func (s Settings) Project() string {
+	return s.ParentProject
+}
+
+func (s Settings) binary() string {
+	if s.Binary == "" {
+		return DefaultBinary
+	}
+	return s.Binary
+}
+
+func (s Settings) urlAttribute() string {
+	if s.URLAttribute == "" {
+		return DefaultURLAttribute
+	}
+	return s.URLAttribute
+}
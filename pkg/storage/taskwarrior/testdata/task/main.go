@@ -0,0 +1,132 @@
+// Command task is a fake taskwarrior used in tests.
+// It supports "export" and "import" with rc overrides, tasks are kept
+// as a JSON array in tasks.json in data location.
+// Arguments of each call are appended to FAKE_TASK_LOG if set.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type task map[string]interface{}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if log := os.Getenv("FAKE_TASK_LOG"); log != "" {
+		f, err := os.OpenFile(log, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(f, strings.Join(args, "\x00"))
+		f.Close()
+	}
+
+	rc := make(map[string]string)
+	command := ""
+	for _, arg := range args {
+		if name, ok := strings.CutPrefix(arg, "rc."); ok {
+			k, v, _ := strings.Cut(name, "=")
+			rc[k] = v
+			continue
+		}
+		if command != "" {
+			return fmt.Errorf("unexpected argument %q", arg)
+		}
+		command = arg
+	}
+	if rc["json.array"] != "on" {
+		return errors.New("json.array must be on")
+	}
+
+	dataDir := rc["data.location"]
+	if dataDir == "" {
+		dataDir = os.Getenv("TASKDATA")
+	}
+	if dataDir == "" {
+		return errors.New("data location is not set")
+	}
+	path := filepath.Join(dataDir, "tasks.json")
+	tasks, err := load(path)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "export":
+		return json.NewEncoder(os.Stdout).Encode(tasks)
+	case "import":
+		var imported []task
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &imported); err != nil {
+			return err
+		}
+		for _, t := range imported {
+			if err := validate(t, rc); err != nil {
+				return err
+			}
+			tasks = upsert(tasks, t)
+		}
+		data, err = json.Marshal(tasks)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, data, 0o600)
+	default:
+		return fmt.Errorf("unsupported command %q", command)
+	}
+}
+
+func load(path string) ([]task, error) {
+	tasks := make([]task, 0)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return tasks, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tasks, json.Unmarshal(data, &tasks)
+}
+
+// validate rejects attributes which are not core ones or declared UDAs.
+func validate(t task, rc map[string]string) error {
+	for k := range t {
+		switch k {
+		case "uuid", "status", "entry", "end", "modified", "description", "project", "tags", "annotations":
+			continue
+		}
+		if rc["uda."+k+".type"] == "" {
+			return fmt.Errorf("unknown attribute %q", k)
+		}
+	}
+	if t["uuid"] == nil || t["description"] == nil {
+		return errors.New("uuid and description are required")
+	}
+	return nil
+}
+
+func upsert(tasks []task, t task) []task {
+	for i := range tasks {
+		if tasks[i]["uuid"] == t["uuid"] {
+			tasks[i] = t
+			return tasks
+		}
+	}
+	return append(tasks, t)
+}
@@ -4,11 +4,11 @@ import (
 	"context"
 	"fmt"
 	"regexp"
-	"time"
 
 	"github.com/gofrs/uuid"
 	todoist "github.com/sachaos/todoist/lib"
 	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/backoff"
 	"github.com/vrutkovs/todohub/pkg/issue"
 )
 
@@ -66,6 +66,7 @@ func New(s *Settings, logger *logrus.Logger) (*Client, error) {
 		AccessToken: s.Token,
 	}
 	client := todoist.NewClient(config)
+	client.Transport = &backoff.Transport{Policy: s.Retry.Policy()}
 	ctx := context.Background()
 	if err := client.Sync(ctx); err != nil {
 		return nil, err
@@ -278,11 +279,11 @@ func (c *Client) Create(sectionName string, item issue.Issue) error {
 		return err
 	}
 	markDownTitle := buildMarkdownLink(item.Title(), item.URL())
-	return c.addItemToSection(markDownTitle, sectionID, sectionName, labelID)
+	return c.addItemToSection(markDownTitle, issue.Description(item), sectionID, sectionName, labelID)
 }
 
 // addItemToSection adds a text card to the list and return a pointer to Card.
-func (c *Client) addItemToSection(text, sectionID, sectionName, labelID string) error {
+func (c *Client) addItemToSection(text, description, sectionID, sectionName, labelID string) error {
 	logger := c.logger.WithField("storage", "todoist").WithField("section", sectionName).WithField("text", text)
 	logger.Info("adding item")
 
@@ -292,7 +293,14 @@ func (c *Client) addItemToSection(text, sectionID, sectionName, labelID string)
 	item.SectionID = sectionID
 	item.LabelNames = []string{labelID}
 
-	if err := c.api.AddItem(context.Background(), item); err != nil {
+	args, ok := item.AddParam().(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected item parameters")
+	}
+	if description != "" {
+		args["description"] = description
+	}
+	if err := c.api.ExecCommands(context.Background(), todoist.Commands{todoist.NewCommand("item_add", args)}); err != nil {
 		logger.WithError(err).Error("failed to create item")
 		return err
 	}
@@ -327,13 +335,12 @@ func (c *Client) Delete(sectionName string, item issue.Issue) error {
 func (c *Client) Sync(description string) error {
 	logger := c.logger.WithField("storage", "todoist").WithField("description", description)
 	logger.Info("syncing")
-	err := c.api.Sync(context.Background())
-	if err != nil {
+	if err := c.api.Sync(context.Background()); err != nil {
 		logger.WithError(err).Error("failed to sync")
-		time.Sleep(time.Minute * 15)
+		return err
 	}
 	logger.Info("done")
-	return err
+	return nil
 }
 
 // CompareByTitleOnly returns true if issues should be compared by title only
@@ -1,10 +1,13 @@
 package todoist
 
+import "github.com/vrutkovs/todohub/pkg/backoff"
+
 // Settings holds info about trello connnection.
 type Settings struct {
-	Token       string `yaml:"token"`
-	ProjectName string `yaml:"project_name,omitempty"`
-	ProjectID   string `yaml:"project_id,omitempty"`
+	Token       string            `yaml:"token"`
+	ProjectName string            `yaml:"project_name,omitempty"`
+	ProjectID   string            `yaml:"project_id,omitempty"`
+	Retry       *backoff.Settings `yaml:"retry,omitempty"`
 }
 
 // Implement storage.Settings.
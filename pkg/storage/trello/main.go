@@ -4,6 +4,7 @@ import (
 	"log"
 
 	api "github.com/adlio/trello"
+	"github.com/vrutkovs/todohub/pkg/backoff"
 	"github.com/vrutkovs/todohub/pkg/issue"
 )
 
@@ -38,6 +39,7 @@ func (c Card) Repo() string {
 // New returns trello client.
 func New(s *Settings) (*Client, error) {
 	clientAPI := api.NewClient(s.AppKey, s.Token)
+	clientAPI.Client = backoff.NewClient(s.Retry.Policy())
 	board, err := clientAPI.GetBoard(s.BoardID, api.Defaults())
 	if err != nil {
 		return nil, err
@@ -134,7 +136,7 @@ func (c *Client) Create(listName string, item issue.Issue) error {
 	if err != nil {
 		return err
 	}
-	card, err := c.addItemToList(item.Title(), listID)
+	card, err := c.addItemToList(item.Title(), issue.Description(item), listID)
 	if err != nil {
 		return err
 	}
@@ -142,7 +144,7 @@ func (c *Client) Create(listName string, item issue.Issue) error {
 }
 
 // AddItemToList adds a text card to the list and return a pointer to Card.
-func (c *Client) addItemToList(item, listID string) (*Card, error) {
+func (c *Client) addItemToList(item, desc, listID string) (*Card, error) {
 	list, err := c.api.GetList(listID, api.Defaults())
 	if err != nil {
 		return nil, err
@@ -161,7 +163,7 @@ func (c *Client) addItemToList(item, listID string) (*Card, error) {
 		}
 	}
 	// Create a new card
-	apiCard := &api.Card{Name: item}
+	apiCard := &api.Card{Name: item, Desc: desc}
 	err = list.AddCard(apiCard, api.Defaults())
 	if err != nil {
 		return nil, err
@@ -1,10 +1,13 @@
 package trello
 
+import "github.com/vrutkovs/todohub/pkg/backoff"
+
 // Settings holds info about trello connnection.
 type Settings struct {
-	AppKey  string `yaml:"appkey"`
-	Token   string `yaml:"token"`
-	BoardID string `yaml:"boardid"`
+	AppKey  string            `yaml:"appkey"`
+	Token   string            `yaml:"token"`
+	BoardID string            `yaml:"boardid"`
+	Retry   *backoff.Settings `yaml:"retry,omitempty"`
 }
 
 // Implement storage.Settings.
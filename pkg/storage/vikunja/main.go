@@ -0,0 +1,355 @@
+package vikunja
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/backoff"
+	"github.com/vrutkovs/todohub/pkg/issue"
+)
+
+// PageSize is a number of items requested per page.
+const PageSize = 50
+
+var linkRegex = regexp.MustCompile(`<a href="([^"]*)"`)
+
+// Client is a wrapper for Vikunja REST API.
+// Lists are projects, repos are labels. Requires Vikunja 0.24 or newer.
+type Client struct {
+	http     *http.Client
+	endpoint *url.URL
+	settings *Settings
+	parentID int64
+	// mu guards projects and labels, which are shared by concurrent source syncs
+	mu       sync.Mutex
+	projects map[string]int64
+	labels   map[string]int64
+	logger   *logrus.Logger
+}
+
+// Item struct holds information about the task.
+type Item struct {
+	id    int64
+	title string
+	url   string
+	repo  string
+}
+
+func (i Item) Title() string {
+	return i.title
+}
+
+func (i Item) URL() string {
+	return i.url
+}
+
+func (i Item) Repo() string {
+	return i.repo
+}
+
+type project struct {
+	ID       int64  `json:"id,omitempty"`
+	Title    string `json:"title"`
+	ParentID int64  `json:"parent_project_id"`
+}
+
+type label struct {
+	ID    int64  `json:"id,omitempty"`
+	Title string `json:"title"`
+}
+
+type task struct {
+	ID          int64   `json:"id,omitempty"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Done        bool    `json:"done,omitempty"`
+	ProjectID   int64   `json:"project_id,omitempty"`
+	Labels      []label `json:"labels,omitempty"`
+}
+
+// New returns Vikunja client.
+func New(s *Settings, logger *logrus.Logger) (*Client, error) {
+	endpoint, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		http:     backoff.NewClient(s.Retry.Policy()),
+		endpoint: endpoint,
+		settings: s,
+		projects: make(map[string]int64),
+		labels:   make(map[string]int64),
+		logger:   logger,
+	}
+	if err := c.loadProjects(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// loadProjects finds parent project and list projects in it.
+func (c *Client) loadProjects() error {
+	var projects []project
+	if err := c.getAll("projects", url.Values{}, &projects); err != nil {
+		return err
+	}
+	if c.settings.ParentProject != "" {
+		for _, p := range projects {
+			if p.ParentID == 0 && p.Title == c.settings.ParentProject {
+				c.parentID = p.ID
+				break
+			}
+		}
+		if c.parentID == 0 {
+			parent, err := c.createProject(c.settings.ParentProject, 0)
+			if err != nil {
+				return err
+			}
+			c.parentID = parent.ID
+		}
+	}
+	for _, p := range projects {
+		if p.ParentID == c.parentID {
+			c.projects[p.Title] = p.ID
+		}
+	}
+	return nil
+}
+
+func (c *Client) createProject(title string, parentID int64) (project, error) {
+	var p project
+	err := c.do(http.MethodPut, "projects", nil, project{Title: title, ParentID: parentID}, &p)
+	return p, err
+}
+
+// CreateProject creates a project for the list if its missing.
+func (c *Client) CreateProject(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.projects[name]; ok {
+		return nil
+	}
+	c.logger.WithFields(logrus.Fields{"storage": "vikunja", "list": name}).Info("creating project")
+	p, err := c.createProject(name, c.parentID)
+	if err != nil {
+		return err
+	}
+	c.projects[name] = p.ID
+	return nil
+}
+
+func (c *Client) projectID(listName string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id, ok := c.projects[listName]
+	if !ok {
+		return 0, fmt.Errorf("vikunja: project %q not found", listName)
+	}
+	return id, nil
+}
+
+// fetchTasks returns all tasks in the list project, including done ones,
+// so that tasks closed by hand are not recreated.
+func (c *Client) fetchTasks(listName string) ([]task, error) {
+	id, err := c.projectID(listName)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []task
+	params := url.Values{}
+	params.Set("filter", fmt.Sprintf("project = %d", id))
+	if err := c.getAll("tasks/all", params, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (c *Client) GetIssues(listName string) ([]issue.Issue, error) {
+	tasks, err := c.fetchTasks(listName)
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]issue.Issue, len(tasks))
+	for i, t := range tasks {
+		item := Item{
+			id:    t.ID,
+			title: t.Title,
+		}
+		if m := linkRegex.FindStringSubmatch(t.Description); m != nil {
+			item.url = html.UnescapeString(m[1])
+		}
+		if len(t.Labels) > 0 {
+			item.repo = t.Labels[0].Title
+		}
+		issues[i] = item
+	}
+	return issues, nil
+}
+
+// description renders issue link and details as HTML.
+func description(item issue.Issue) string {
+	var b strings.Builder
+	if item.URL() != "" {
+		u := html.EscapeString(item.URL())
+		fmt.Fprintf(&b, `<p><a href="%s">%s</a></p>`, u, u)
+	}
+	if d := issue.Description(item); d != "" {
+		fmt.Fprintf(&b, "<p>%s</p>", strings.ReplaceAll(html.EscapeString(d), "\n", "<br>"))
+	}
+	return b.String()
+}
+
+// Create adds a task with issue link and repo label.
+func (c *Client) Create(listName string, item issue.Issue) error {
+	id, err := c.projectID(listName)
+	if err != nil {
+		return err
+	}
+	var created task
+	err = c.do(http.MethodPut, fmt.Sprintf("projects/%d/tasks", id), nil, task{
+		Title:       item.Title(),
+		Description: description(item),
+	}, &created)
+	if err != nil {
+		return err
+	}
+	if item.Repo() == "" {
+		return nil
+	}
+	labelID, err := c.ensureLabel(item.Repo())
+	if err != nil {
+		return err
+	}
+	return c.do(http.MethodPut, fmt.Sprintf("tasks/%d/labels", created.ID), nil, map[string]int64{"label_id": labelID}, nil)
+}
+
+// ensureLabel returns label ID by title, creating it if its missing.
+func (c *Client) ensureLabel(title string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if id, ok := c.labels[title]; ok {
+		return id, nil
+	}
+	var labels []label
+	params := url.Values{}
+	params.Set("s", title)
+	if err := c.getAll("labels", params, &labels); err != nil {
+		return 0, err
+	}
+	for _, l := range labels {
+		if l.Title == title {
+			c.labels[title] = l.ID
+			return l.ID, nil
+		}
+	}
+	var created label
+	if err := c.do(http.MethodPut, "labels", nil, label{Title: title}, &created); err != nil {
+		return 0, err
+	}
+	c.labels[title] = created.ID
+	return created.ID, nil
+}
+
+// Delete removes tasks with matching title.
+func (c *Client) Delete(listName string, item issue.Issue) error {
+	tasks, err := c.fetchTasks(listName)
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		if t.Title != item.Title() {
+			continue
+		}
+		if err := c.do(http.MethodDelete, fmt.Sprintf("tasks/%d", t.ID), nil, nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sync ensures changes are committed.
+func (c *Client) Sync(_ string) error {
+	// vikunja changes are applied immediately
+	return nil
+}
+
+// CompareByTitleOnly returns true if issues should be compared by title only
+// Some storages may not be able to fetch other details like URL in GetIssues.
+func (c *Client) CompareByTitleOnly() bool {
+	return true
+}
+
+// getAll fetches all pages of a list endpoint into result slice.
+func (c *Client) getAll(path string, params url.Values, result interface{}) error {
+	all := make([]json.RawMessage, 0)
+	params.Set("per_page", strconv.Itoa(PageSize))
+	for page := 1; ; page++ {
+		params.Set("page", strconv.Itoa(page))
+		var items []json.RawMessage
+		if err := c.do(http.MethodGet, path, params, nil, &items); err != nil {
+			return err
+		}
+		all = append(all, items...)
+		if len(items) < PageSize {
+			break
+		}
+	}
+	data, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
+
+// do sends authenticated JSON request and decodes response into result.
+func (c *Client) do(method, path string, params url.Values, body, result interface{}) error {
+	u := c.endpoint.JoinPath("api", "v1", path)
+	if params != nil {
+		u.RawQuery = params.Encode()
+	}
+	var reqBody io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(context.Background(), method, u.String(), reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.settings.Token)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("vikunja: %s %s: %s: %s", method, u.Path, resp.Status, apiErr.Message)
+		}
+		return fmt.Errorf("vikunja: %s %s: %s", method, u.Path, resp.Status)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,237 @@
+package vikunja
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/issue"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestVikunja(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Vikunja")
+}
+
+type testIssue struct {
+	title string
+	url   string
+	repo  string
+}
+
+func (i testIssue) Title() string { return i.title }
+func (i testIssue) URL() string   { return i.url }
+func (i testIssue) Repo() string  { return i.repo }
+
+// fakeServer keeps Vikunja projects, labels and tasks in memory.
+type fakeServer struct {
+	mu       sync.Mutex
+	nextID   int64
+	projects []project
+	labels   []label
+	tasks    []task
+}
+
+func (f *fakeServer) id() int64 {
+	f.nextID++
+	return f.nextID
+}
+
+// page returns requested page of items.
+func page[T any](r *http.Request, items []T) []T {
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	p, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	start := (p - 1) * perPage
+	if start >= len(items) {
+		return []T{}
+	}
+	return items[start:min(start+perPage, len(items))]
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"message":"missing, malformed, expired or otherwise invalid token provided"}`)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/")
+	var result interface{}
+	switch {
+	case r.Method == http.MethodGet && path == "projects":
+		result = page(r, f.projects)
+	case r.Method == http.MethodPut && path == "projects":
+		var p project
+		Expect(json.NewDecoder(r.Body).Decode(&p)).To(Succeed())
+		p.ID = f.id()
+		f.projects = append(f.projects, p)
+		result = p
+	case r.Method == http.MethodGet && path == "tasks/all":
+		var projectID int64
+		_, err := fmt.Sscanf(r.URL.Query().Get("filter"), "project = %d", &projectID)
+		Expect(err).NotTo(HaveOccurred())
+		tasks := make([]task, 0)
+		for _, t := range f.tasks {
+			if t.ProjectID == projectID {
+				tasks = append(tasks, t)
+			}
+		}
+		result = page(r, tasks)
+	case r.Method == http.MethodPut && strings.HasPrefix(path, "projects/") && strings.HasSuffix(path, "/tasks"):
+		var t task
+		Expect(json.NewDecoder(r.Body).Decode(&t)).To(Succeed())
+		projectID, err := strconv.ParseInt(strings.Split(path, "/")[1], 10, 64)
+		Expect(err).NotTo(HaveOccurred())
+		t.ID = f.id()
+		t.ProjectID = projectID
+		f.tasks = append(f.tasks, t)
+		result = t
+	case r.Method == http.MethodGet && path == "labels":
+		labels := make([]label, 0)
+		for _, l := range f.labels {
+			if strings.Contains(l.Title, r.URL.Query().Get("s")) {
+				labels = append(labels, l)
+			}
+		}
+		result = page(r, labels)
+	case r.Method == http.MethodPut && path == "labels":
+		var l label
+		Expect(json.NewDecoder(r.Body).Decode(&l)).To(Succeed())
+		l.ID = f.id()
+		f.labels = append(f.labels, l)
+		result = l
+	case r.Method == http.MethodPut && strings.HasSuffix(path, "/labels"):
+		var body map[string]int64
+		Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+		taskID, err := strconv.ParseInt(strings.Split(path, "/")[1], 10, 64)
+		Expect(err).NotTo(HaveOccurred())
+		for i, t := range f.tasks {
+			if t.ID != taskID {
+				continue
+			}
+			for _, l := range f.labels {
+				if l.ID == body["label_id"] {
+					f.tasks[i].Labels = append(f.tasks[i].Labels, l)
+				}
+			}
+		}
+		result = body
+	case r.Method == http.MethodDelete && strings.HasPrefix(path, "tasks/"):
+		taskID, err := strconv.ParseInt(strings.TrimPrefix(path, "tasks/"), 10, 64)
+		Expect(err).NotTo(HaveOccurred())
+		tasks := make([]task, 0)
+		for _, t := range f.tasks {
+			if t.ID != taskID {
+				tasks = append(tasks, t)
+			}
+		}
+		f.tasks = tasks
+		result = map[string]string{"message": "Successfully deleted."}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"Not found"}`)
+		return
+	}
+	Expect(json.NewEncoder(w).Encode(result)).To(Succeed())
+}
+
+var _ = Describe("Client", func() {
+	var (
+		fake *fakeServer
+		srv  *httptest.Server
+	)
+
+	BeforeEach(func() {
+		fake = &fakeServer{nextID: 100}
+		fake.projects = []project{{ID: 1, Title: "Inbox"}}
+		for i := 0; i < PageSize; i++ {
+			fake.projects = append(fake.projects, project{ID: int64(i + 2), Title: fmt.Sprintf("Project %d", i)})
+		}
+		srv = httptest.NewServer(fake)
+	})
+
+	AfterEach(func() {
+		srv.Close()
+	})
+
+	It("creates parent and list projects", func() {
+		client, err := New(&Settings{Endpoint: srv.URL, Token: "token", ParentProject: "todohub"}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.CreateProject("To review")).To(Succeed())
+		Expect(client.CreateProject("To review")).To(Succeed())
+		Expect(fake.projects[len(fake.projects)-2:]).To(Equal([]project{
+			{ID: 101, Title: "todohub"},
+			{ID: 102, Title: "To review", ParentID: 101},
+		}))
+
+		// Existing projects are found on restart
+		client, err = New(&Settings{Endpoint: srv.URL, Token: "token", ParentProject: "todohub"}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.projects).To(Equal(map[string]int64{"To review": 102}))
+	})
+
+	It("uses top level projects without parent", func() {
+		client, err := New(&Settings{Endpoint: srv.URL, Token: "token"}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.projects).To(HaveKeyWithValue("Inbox", int64(1)))
+		Expect(client.projects).To(HaveKeyWithValue(fmt.Sprintf("Project %d", PageSize-1), int64(PageSize+1)))
+	})
+
+	It("creates, lists and deletes tasks", func() {
+		client, err := New(&Settings{Endpoint: srv.URL, Token: "token"}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Create("Inbox", testIssue{title: "Fix", url: "https://github.com/o/r/pull/1?a=1&b=2", repo: "o/r"})).To(Succeed())
+		Expect(client.Create("Inbox", testIssue{title: "Bump", url: "https://github.com/o/r/pull/2", repo: "o/r"})).To(Succeed())
+		Expect(fake.labels).To(Equal([]label{{ID: 102, Title: "o/r"}}))
+
+		issues, err := client.GetIssues("Inbox")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(ConsistOf(
+			issue.Issue(Item{id: 101, title: "Fix", url: "https://github.com/o/r/pull/1?a=1&b=2", repo: "o/r"}),
+			issue.Issue(Item{id: 103, title: "Bump", url: "https://github.com/o/r/pull/2", repo: "o/r"}),
+		))
+
+		Expect(client.Delete("Inbox", testIssue{title: "Fix"})).To(Succeed())
+		issues, err = client.GetIssues("Inbox")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0].Title()).To(Equal("Bump"))
+	})
+
+	It("creates projects and labels once for concurrent syncs", func() {
+		client, err := New(&Settings{Endpoint: srv.URL, Token: "token"}, logrus.New())
+		Expect(err).NotTo(HaveOccurred())
+		var wg sync.WaitGroup
+		for n := range 4 {
+			wg.Add(1)
+			go func(n int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				Expect(client.CreateProject("To review")).To(Succeed())
+				Expect(client.Create("To review", testIssue{title: fmt.Sprintf("Fix %d", n), repo: "o/r"})).To(Succeed())
+			}(n)
+		}
+		wg.Wait()
+
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		Expect(fake.projects).To(HaveLen(PageSize + 2))
+		Expect(fake.labels).To(HaveLen(1))
+		Expect(fake.tasks).To(HaveLen(4))
+	})
+
+	It("returns API error message", func() {
+		_, err := New(&Settings{Endpoint: srv.URL, Token: "wrong"}, logrus.New())
+		Expect(err).To(MatchError(ContainSubstring("invalid token provided")))
+	})
+})
@@ -0,0 +1,23 @@
+package vikunja
+
+import "github.com/vrutkovs/todohub/pkg/backoff"
+
+// Settings holds info about Vikunja connection.
+type Settings struct {
+	// Endpoint is Vikunja URL, e.g. https://vikunja.example.com
+	Endpoint string `yaml:"endpoint"`
+	// Token is an API token with projects, tasks and labels permissions.
+	Token string `yaml:"token"`
+	// ParentProject is a project title holding list projects, top level projects are used if empty.
+	ParentProject string            `yaml:"parent_project,omitempty"`
+	Retry         *backoff.Settings `yaml:"retry,omitempty"`
+}
+
+// Implement storage.Settings.
+func (s Settings) ID() string {
+	return "vikunja"
+}
+
+func (s Settings) Project() string {
+	return s.ParentProject
+}
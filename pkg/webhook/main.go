@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultDebounceSeconds is a default delay before webhook-triggered sync starts.
+const DefaultDebounceSeconds = 10
+
+// MaxPayloadBytes limits webhook request body size.
+const MaxPayloadBytes = 25 << 20
+
+// Debouncer collects keys and runs the callback once no new keys arrived within the delay.
+type Debouncer struct {
+	mu      sync.Mutex
+	delay   time.Duration
+	pending map[string]struct{}
+	timer   *time.Timer
+	run     func(keys []string)
+}
+
+// NewDebouncer returns a debouncer calling run with collected keys.
+func NewDebouncer(delay time.Duration, run func(keys []string)) *Debouncer {
+	return &Debouncer{
+		delay:   delay,
+		pending: make(map[string]struct{}),
+		run:     run,
+	}
+}
+
+// Add schedules keys for the next run and postpones it.
+func (d *Debouncer) Add(keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, key := range keys {
+		d.pending[key] = struct{}{}
+	}
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.timer = time.AfterFunc(d.delay, d.fire)
+}
+
+// fire runs the callback with all pending keys.
+func (d *Debouncer) fire() {
+	d.mu.Lock()
+	keys := make([]string, 0, len(d.pending))
+	for key := range d.pending {
+		keys = append(keys, key)
+	}
+	d.pending = make(map[string]struct{})
+	d.timer = nil
+	d.mu.Unlock()
+
+	if len(keys) == 0 {
+		return
+	}
+	sort.Strings(keys)
+	d.run(keys)
+}
+
+// Delay converts seconds from settings into debounce delay.
+func Delay(seconds int) time.Duration {
+	if seconds <= 0 {
+		seconds = DefaultDebounceSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,37 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestWebhook(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Webhook")
+}
+
+var _ = Describe("Debouncer", func() {
+	It("merges keys added within delay", func() {
+		runs := make(chan []string, 2)
+		d := NewDebouncer(50*time.Millisecond, func(keys []string) {
+			runs <- keys
+		})
+		d.Add("b")
+		d.Add("a", "b")
+		d.Add()
+
+		Eventually(runs).Should(Receive(Equal([]string{"a", "b"})))
+		Consistently(runs, 100*time.Millisecond).ShouldNot(Receive())
+
+		d.Add("c")
+		Eventually(runs).Should(Receive(Equal([]string{"c"})))
+	})
+
+	It("uses default delay", func() {
+		Expect(Delay(0)).To(Equal(DefaultDebounceSeconds * time.Second))
+		Expect(Delay(3)).To(Equal(3 * time.Second))
+	})
+})
@@ -0,0 +1,7 @@
+package webhook
+
+// Settings holds webhook receiver settings.
+type Settings struct {
+	Secret          string `yaml:"secret"`
+	DebounceSeconds int    `yaml:"debounce_seconds,omitempty"`
+}
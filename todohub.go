@@ -2,12 +2,23 @@ package main
 
 import (
 	"os"
+	"time"
 
 	"github.com/jasonlvhit/gocron"
 	"github.com/sirupsen/logrus"
+	"github.com/vrutkovs/todohub/pkg/calendar"
+	"github.com/vrutkovs/todohub/pkg/dashboard"
+	"github.com/vrutkovs/todohub/pkg/health"
+	"github.com/vrutkovs/todohub/pkg/server"
 	"github.com/vrutkovs/todohub/pkg/settings"
+	"github.com/vrutkovs/todohub/pkg/source/bitbucket"
+	"github.com/vrutkovs/todohub/pkg/source/bugzilla"
+	"github.com/vrutkovs/todohub/pkg/source/feed"
+	"github.com/vrutkovs/todohub/pkg/source/gerrit"
+	"github.com/vrutkovs/todohub/pkg/source/gitea"
 	"github.com/vrutkovs/todohub/pkg/source/github"
 	"github.com/vrutkovs/todohub/pkg/source/jira"
+	"github.com/vrutkovs/todohub/pkg/storage"
 )
 
 func main() {
@@ -22,33 +33,139 @@ func main() {
 		logger.Fatal(err)
 	}
 
+	// Expose metrics and health checks
+	var healthIntervals int
+	if s.Server != nil {
+		healthIntervals = s.Server.HealthIntervals
+	}
+	tracker := health.New(time.Duration(s.SyncTimeout)*time.Minute, healthIntervals)
+	var srv *server.Server
+	if s.Server != nil {
+		srv = server.New(s.Server, logger)
+		srv.Handle("/healthz", tracker.LivenessHandler())
+		srv.Handle("/readyz", tracker.ReadinessHandler())
+	}
+
+	// Render lists of all sources as a static page
+	var observers []storage.ListObserver
+	if s.Dashboard != nil {
+		d, err := dashboard.New(s.Dashboard, logger)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		observers = append(observers, d)
+		if srv != nil {
+			srv.Handle("/dashboard", d)
+		}
+	}
+
+	// Publish items with due dates as iCalendar feed
+	if s.Calendar != nil {
+		feed, err := calendar.New(s.Calendar, logger)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		observers = append(observers, feed)
+		if srv != nil {
+			srv.Handle("/calendar.ics", feed)
+		}
+	}
+	if srv != nil {
+		srv.Start()
+	}
+
 	// Find active storage
 	storageClient, err := s.Storage.GetActiveStorageClient(logger)
 	if err != nil {
 		logger.Fatal(err)
 	}
+	if len(observers) > 0 {
+		storageClient = storage.NewObserved(storageClient, observers...)
+	}
+	tracker.StorageConnected()
 
-	if s.Source.Github != nil {
-		gh := github.New(s.Source.Github, storageClient, logger)
-		if err := gocron.Every(s.SyncTimeout).Minutes().Do(gh.Sync, "periodically"); err != nil {
+	// schedule runs source sync on startup and then periodically
+	schedule := func(source string, syncFunc health.SyncFunc) {
+		trackedSync := tracker.Track(source, syncFunc)
+		if err := gocron.Every(s.SyncTimeout).Minutes().Do(trackedSync, "periodically"); err != nil {
 			logger.Fatal(err)
 		}
-		if err := gh.Sync("on startup"); err != nil {
+		if err := trackedSync("on startup"); err != nil {
 			logger.Fatal(err)
 		}
 	}
 
+	if s.Source.Github != nil {
+		gh := github.New(s.Source.Github, storageClient, logger)
+		if s.Source.Github.Webhook != nil {
+			if srv == nil {
+				logger.Fatal("github webhook requires server settings")
+			}
+			handler, err := gh.WebhookHandler()
+			if err != nil {
+				logger.Fatal(err)
+			}
+			srv.Handle("/webhooks/github", handler)
+		}
+		schedule("github", gh.Sync)
+	}
+
 	if s.Source.Jira != nil {
 		jiraSource, err := jira.New(s.Source.Jira, storageClient, logger)
 		if err != nil {
 			logger.Fatal(err)
 		}
-		if err := gocron.Every(s.SyncTimeout).Minutes().Do(jiraSource.Sync, "periodically"); err != nil {
+		if s.Source.Jira.Webhook != nil {
+			if srv == nil {
+				logger.Fatal("jira webhook requires server settings")
+			}
+			handler, err := jiraSource.WebhookHandler()
+			if err != nil {
+				logger.Fatal(err)
+			}
+			srv.Handle("/webhooks/jira", handler)
+		}
+		schedule("jira", jiraSource.Sync)
+	}
+
+	if s.Source.Gitea != nil {
+		giteaSource, err := gitea.New(s.Source.Gitea, storageClient, logger)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		schedule("gitea", giteaSource.Sync)
+	}
+
+	if s.Source.Bugzilla != nil {
+		bugzillaSource, err := bugzilla.New(s.Source.Bugzilla, storageClient, logger)
+		if err != nil {
 			logger.Fatal(err)
 		}
-		if err := jiraSource.Sync("on startup"); err != nil {
+		schedule("bugzilla", bugzillaSource.Sync)
+	}
+
+	if s.Source.Gerrit != nil {
+		gerritSource, err := gerrit.New(s.Source.Gerrit, storageClient, logger)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		schedule("gerrit", gerritSource.Sync)
+	}
+
+	if s.Source.Feed != nil {
+		feedSource, err := feed.New(s.Source.Feed, storageClient, logger)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		schedule("feed", feedSource.Sync)
+	}
+
+	if s.Source.Bitbucket != nil {
+		bitbucketSource, err := bitbucket.New(s.Source.Bitbucket, storageClient, logger)
+		if err != nil {
 			logger.Fatal(err)
 		}
+		schedule("bitbucket", bitbucketSource.Sync)
 	}
 
 	// Start cron